@@ -22,6 +22,10 @@ type HypervClient struct {
     User       string
     Password   string
     Insecure   bool
+
+    // defaults for resources
+    DefaultTags map[string]string   // tags added to all resources supporting tags
+    NamePrefix  string              // prefix added to the names of the managed hyperv objects
 }
 
 //------------------------------------------------------------------------------
@@ -0,0 +1,95 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "encoding/base64"
+    "io"
+    "io/ioutil"
+    "log"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+// runDetached has the same signature as runner.Run, but runs the script in a scheduled task on the hyperv-server
+// the scheduled task survives a disconnect of the provider's connection, f.i. when the script changes the network adapter carrying that connection
+// remark that runDetached returns as soon as the scheduled task is registered, it doesn't wait for the script to finish
+func runDetached(connection interface{}, s *script.Script, arguments interface{}, stdout, stderr io.Writer) error {
+    if s.Error != nil {
+        return s.Error
+    }
+
+    // render the script
+    reader, err := s.NewReader(arguments)
+    if err != nil {
+        return err
+    }
+    code, err := ioutil.ReadAll(reader)
+    if err != nil {
+        return err
+    }
+
+    // run the script in a scheduled task
+    err = runner.Run(connection, runDetachedScript, runDetachedArguments{
+        Name:       s.Name,
+        CodeBase64: base64.StdEncoding.EncodeToString(code),
+    }, stdout, stderr)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/runDetached()] cannot schedule detached script %q\n", s.Name)
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/runDetached()] scheduled detached script %q\n", s.Name)
+    return nil
+}
+
+type runDetachedArguments struct{
+    Name       string
+    CodeBase64 string
+}
+
+var runDetachedScript = script.New("runDetached", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$taskName = "terraform-provider-hyperv-{{.Name}}-$( [guid]::NewGuid() )"
+$taskPath = Join-Path $env:ProgramData 'terraform-provider-hyperv'
+New-Item -Path $taskPath -ItemType 'Directory' -Force | Out-Null
+
+$codePath     = Join-Path $taskPath "$taskName.ps1"
+$launcherPath = Join-Path $taskPath "$taskName-launcher.ps1"
+$logPath      = Join-Path $taskPath "$taskName.log"
+
+$code = [System.Text.Encoding]::UTF8.GetString( [System.Convert]::FromBase64String( '{{.CodeBase64}}' ) )
+Set-Content -Path $codePath -Value $code -Encoding 'UTF8'
+
+# the launcher waits to give the provider the time to disconnect cleanly, runs the code, logs the result and cleans up
+$launcher = @"
+Start-Sleep -Seconds 5
+try {
+    & '$codePath' *> '$logPath'
+    Add-Content -Path '$logPath' -Value 'completed'
+} catch {
+    Add-Content -Path '$logPath' -Value "failed: `+"`"+`$( `+"`"+`$_ )"
+} finally {
+    Unregister-ScheduledTask -TaskName '$taskName' -Confirm:`+"`"+`$false
+    Remove-Item -Path '$codePath', '$launcherPath' -Force
+}
+"@
+Set-Content -Path $launcherPath -Value $launcher -Encoding 'UTF8'
+
+$action    = New-ScheduledTaskAction -Execute 'PowerShell.exe' -Argument "-NoProfile -ExecutionPolicy ByPass -File ""$launcherPath"""
+$principal = New-ScheduledTaskPrincipal -UserId 'SYSTEM' -LogonType 'ServiceAccount' -RunLevel 'Highest'
+Register-ScheduledTask -TaskName $taskName -Action $action -Principal $principal | Out-Null
+Start-ScheduledTask -TaskName $taskName
+
+Write-Output $taskName
+`)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,368 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type HostIPConfiguration struct {
+    InterfaceAlias                 string   // required, f.i. "vEthernet (Internal Switch)"
+    IPAddresses                    []HostIPAddress
+    DnsServerAddresses             []string   // when reading, only for the address families of the IPAddresses
+
+    // computed
+    InterfaceIndex                 int
+}
+
+type HostIPAddress struct {
+    IPAddress                      string   // IPv4 or IPv6
+    PrefixLength                   int
+}
+
+//------------------------------------------------------------------------------
+
+func (c *HypervClient) CreateHostIPConfiguration(hipcProperties *HostIPConfiguration) error {
+    if hipcProperties.InterfaceAlias == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateHostIPConfiguration(hipcProperties)] missing 'hipcProperties.InterfaceAlias'")
+    }
+
+    return createHostIPConfiguration(c, hipcProperties)
+}
+
+func (c *HypervClient) ReadHostIPConfiguration(hipc *HostIPConfiguration) (hostIPConfiguration *HostIPConfiguration, err error) {
+    if hipc.InterfaceAlias == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/hipc.Read()] missing 'hipc.InterfaceAlias'")
+    }
+
+    return readHostIPConfiguration(c, hipc)
+}
+
+func (c *HypervClient) UpdateHostIPConfiguration(hipc *HostIPConfiguration, hipcProperties *HostIPConfiguration) error {
+    if hipc.InterfaceAlias == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/hipc.Update(hipcProperties)] missing 'hipc.InterfaceAlias'")
+    }
+
+    return updateHostIPConfiguration(c, hipc, hipcProperties)
+}
+
+func (c *HypervClient) DeleteHostIPConfiguration(hipc *HostIPConfiguration) error {
+    if hipc.InterfaceAlias == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/hipc.Delete()] missing 'hipc.InterfaceAlias'")
+    }
+
+    return deleteHostIPConfiguration(c, hipc)
+}
+
+//------------------------------------------------------------------------------
+
+func createHostIPConfiguration(c *HypervClient, hipcProperties *HostIPConfiguration) error {
+    // convert hipcProperties to JSON
+    hipcPropertiesJSON, err := json.Marshal(hipcProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createHostIPConfiguration()] cannot cannot convert 'hipcProperties' to json for %q\n", hipcProperties.InterfaceAlias)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, createHostIPConfigurationScript, createHostIPConfigurationArguments{
+        HIPCPropertiesJSON: string(hipcPropertiesJSON),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createHostIPConfiguration()] cannot create host ip configuration for %q\n", hipcProperties.InterfaceAlias)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createHostIPConfiguration()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createHostIPConfiguration()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createHostIPConfiguration()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/createHostIPConfiguration()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/createHostIPConfiguration()] created host ip configuration for %q\n", hipcProperties.InterfaceAlias)
+    return nil
+}
+
+type createHostIPConfigurationArguments struct{
+    HIPCPropertiesJSON string
+}
+
+var createHostIPConfigurationScript = script.New("createHostIPConfiguration", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$hipcProperties = $( ConvertFrom-Json -InputObject '{{.HIPCPropertiesJSON}}' )
+
+$NetAdapterObject = Get-NetAdapter -Name $hipcProperties.InterfaceAlias -ErrorAction 'Ignore'
+if ( -not $NetAdapterObject ) {
+    throw "cannot find host interface '$( $hipcProperties.InterfaceAlias )'"
+}
+
+$NetIPAddressObjects = Get-NetIPAddress -InterfaceAlias $hipcProperties.InterfaceAlias -PrefixOrigin 'Manual' -ErrorAction 'Ignore'
+if ( $NetIPAddressObjects ) {
+    throw "host ip configuration for '$( $hipcProperties.InterfaceAlias )' already exists"
+}
+
+# disable DHCP for the address families of the configured addresses only
+$AddressFamilies = @( $hipcProperties.IPAddresses | ForEach-Object { if ( $_.IPAddress.Contains(':') ) { 'IPv6' } else { 'IPv4' } } | Select-Object -Unique )
+foreach ( $AddressFamily in $AddressFamilies ) {
+    Set-NetIPInterface -InterfaceAlias $hipcProperties.InterfaceAlias -AddressFamily $AddressFamily -Dhcp 'Disabled' | Out-Default
+}
+
+foreach ( $ipAddress in $hipcProperties.IPAddresses ) {
+    New-NetIPAddress -InterfaceAlias $hipcProperties.InterfaceAlias -IPAddress $ipAddress.IPAddress -PrefixLength $ipAddress.PrefixLength | Out-Null
+}
+
+if ( $hipcProperties.DnsServerAddresses ) {
+    Set-DnsClientServerAddress -InterfaceAlias $hipcProperties.InterfaceAlias -ServerAddresses $hipcProperties.DnsServerAddresses | Out-Default
+}
+`)
+
+//------------------------------------------------------------------------------
+
+func readHostIPConfiguration(c *HypervClient, hipc *HostIPConfiguration) (hostIPConfiguration *HostIPConfiguration, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readHostIPConfigurationScript, readHostIPConfigurationArguments{
+        InterfaceAlias: hipc.InterfaceAlias,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readHostIPConfiguration()] cannot read host ip configuration for %q\n", hipc.InterfaceAlias)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readHostIPConfiguration()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readHostIPConfiguration()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readHostIPConfiguration()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readHostIPConfiguration()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to hostIPConfiguration
+    hostIPConfiguration = new(HostIPConfiguration)
+    err = json.Unmarshal(stdout.Bytes(), hostIPConfiguration)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readHostIPConfiguration()] cannot convert json to 'hostIPConfiguration' for %q\n", hipc.InterfaceAlias)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readHostIPConfiguration()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readHostIPConfiguration()] read host ip configuration for %q\n", hipc.InterfaceAlias)
+    return hostIPConfiguration, nil
+}
+
+type readHostIPConfigurationArguments struct{
+    InterfaceAlias string
+}
+
+var readHostIPConfigurationScript = script.New("readHostIPConfiguration", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$NetAdapterObject = Get-NetAdapter -Name '{{.InterfaceAlias}}' -ErrorAction 'Ignore'
+if ( -not $NetAdapterObject ) {
+    throw "cannot find host interface '{{.InterfaceAlias}}'"
+}
+
+$NetIPAddressObjects = @( Get-NetIPAddress -InterfaceAlias '{{.InterfaceAlias}}' -PrefixOrigin 'Manual' -ErrorAction 'Ignore' )
+
+# only read the dns servers for the address families of the managed ip addresses,
+# the dns servers for the other families are defaults (f.i. 'fec0:0:0:ffff::1') or assigned by DHCP
+$AddressFamilies = @( $NetIPAddressObjects | ForEach-Object { $_.AddressFamily.ToString() } | Select-Object -Unique )
+
+$HostIPConfiguration = @{
+    InterfaceAlias     = $NetAdapterObject.Name
+    InterfaceIndex     = $NetAdapterObject.InterfaceIndex
+    IPAddresses        = @( $NetIPAddressObjects | ForEach-Object {
+        @{
+            IPAddress    = $_.IPAddress
+            PrefixLength = [int]$_.PrefixLength
+        }
+    } )
+    DnsServerAddresses = @( $AddressFamilies | ForEach-Object { Get-DnsClientServerAddress -InterfaceAlias '{{.InterfaceAlias}}' -AddressFamily $_ -ErrorAction 'Ignore' } | ForEach-Object { $_.ServerAddresses } | Where-Object { $_ } )
+}
+
+Write-Output $( ConvertTo-Json -InputObject $HostIPConfiguration -Depth 4 )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateHostIPConfiguration(c *HypervClient, hipc *HostIPConfiguration, hipcProperties *HostIPConfiguration) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // convert hipcProperties to JSON
+    hipcPropertiesJSON, err := json.Marshal(hipcProperties)
+    if err != nil {
+        return err
+    }
+
+    // run script
+    err = runner.Run(c, updateHostIPConfigurationScript, updateHostIPConfigurationArguments{
+        InterfaceAlias:     hipc.InterfaceAlias,
+        HIPCPropertiesJSON: string(hipcPropertiesJSON),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateHostIPConfiguration()] cannot update host ip configuration for %q\n", hipc.InterfaceAlias)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateHostIPConfiguration()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateHostIPConfiguration()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateHostIPConfiguration()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/updateHostIPConfiguration()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/updateHostIPConfiguration()] updated host ip configuration for %q\n", hipc.InterfaceAlias)
+    return nil
+}
+
+type updateHostIPConfigurationArguments struct{
+    InterfaceAlias     string
+    HIPCPropertiesJSON string
+}
+
+var updateHostIPConfigurationScript = script.New("updateHostIPConfiguration", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$NetAdapterObject = Get-NetAdapter -Name '{{.InterfaceAlias}}' -ErrorAction 'Ignore'
+if ( -not $NetAdapterObject ) {
+    throw "cannot find host interface '{{.InterfaceAlias}}'"
+}
+
+$hipcProperties = $( ConvertFrom-Json -InputObject '{{.HIPCPropertiesJSON}}' )
+
+$NetIPAddressObjects = @( Get-NetIPAddress -InterfaceAlias '{{.InterfaceAlias}}' -PrefixOrigin 'Manual' -ErrorAction 'Ignore' )
+
+# disable DHCP for the address families that are added, enable DHCP for the address families that are removed
+$OldAddressFamilies = @( $NetIPAddressObjects | ForEach-Object { $_.AddressFamily.ToString() } | Select-Object -Unique )
+$AddressFamilies = @( $hipcProperties.IPAddresses | ForEach-Object { if ( $_.IPAddress.Contains(':') ) { 'IPv6' } else { 'IPv4' } } | Select-Object -Unique )
+foreach ( $AddressFamily in $AddressFamilies ) {
+    if ( $OldAddressFamilies -notcontains $AddressFamily ) {
+        Set-NetIPInterface -InterfaceAlias '{{.InterfaceAlias}}' -AddressFamily $AddressFamily -Dhcp 'Disabled' | Out-Default
+    }
+}
+
+# remove addresses that are no longer configured
+foreach ( $NetIPAddressObject in $NetIPAddressObjects ) {
+    if ( -not ( $hipcProperties.IPAddresses | Where-Object { $_.IPAddress -eq $NetIPAddressObject.IPAddress } ) ) {
+        Remove-NetIPAddress -InputObject $NetIPAddressObject -Confirm:$false | Out-Default
+    }
+}
+
+# add new addresses and update the prefix length of existing addresses
+foreach ( $ipAddress in $hipcProperties.IPAddresses ) {
+    $NetIPAddressObject = $NetIPAddressObjects | Where-Object { $_.IPAddress -eq $ipAddress.IPAddress }
+    if ( -not $NetIPAddressObject ) {
+        New-NetIPAddress -InterfaceAlias '{{.InterfaceAlias}}' -IPAddress $ipAddress.IPAddress -PrefixLength $ipAddress.PrefixLength | Out-Null
+    } elseif ( $NetIPAddressObject.PrefixLength -ne $ipAddress.PrefixLength ) {
+        Set-NetIPAddress -InputObject $NetIPAddressObject -PrefixLength $ipAddress.PrefixLength | Out-Default
+    }
+}
+
+foreach ( $AddressFamily in $OldAddressFamilies ) {
+    if ( $AddressFamilies -notcontains $AddressFamily ) {
+        Set-NetIPInterface -InterfaceAlias '{{.InterfaceAlias}}' -AddressFamily $AddressFamily -Dhcp 'Enabled' | Out-Default
+    }
+}
+
+if ( $hipcProperties.DnsServerAddresses ) {
+    Set-DnsClientServerAddress -InterfaceAlias '{{.InterfaceAlias}}' -ServerAddresses $hipcProperties.DnsServerAddresses | Out-Default
+} else {
+    Set-DnsClientServerAddress -InterfaceAlias '{{.InterfaceAlias}}' -ResetServerAddresses | Out-Default
+}
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteHostIPConfiguration(c *HypervClient, hipc *HostIPConfiguration) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err := runner.Run(c, deleteHostIPConfigurationScript, deleteHostIPConfigurationArguments{
+        InterfaceAlias: hipc.InterfaceAlias,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteHostIPConfiguration()] cannot delete host ip configuration for %q\n", hipc.InterfaceAlias)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteHostIPConfiguration()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteHostIPConfiguration()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteHostIPConfiguration()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/deleteHostIPConfiguration()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/deleteHostIPConfiguration()] deleted host ip configuration for %q\n", hipc.InterfaceAlias)
+    return nil
+}
+
+type deleteHostIPConfigurationArguments struct{
+    InterfaceAlias string
+}
+
+var deleteHostIPConfigurationScript = script.New("deleteHostIPConfiguration", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$NetAdapterObject = Get-NetAdapter -Name '{{.InterfaceAlias}}' -ErrorAction 'Ignore'
+if ( -not $NetAdapterObject ) {
+    throw "cannot find host interface '{{.InterfaceAlias}}'"
+}
+
+$NetIPAddressObjects = @( Get-NetIPAddress -InterfaceAlias '{{.InterfaceAlias}}' -PrefixOrigin 'Manual' -ErrorAction 'Ignore' )
+$AddressFamilies = @( $NetIPAddressObjects | ForEach-Object { $_.AddressFamily.ToString() } | Select-Object -Unique )
+
+$NetIPAddressObjects | Remove-NetIPAddress -Confirm:$false | Out-Default
+Set-DnsClientServerAddress -InterfaceAlias '{{.InterfaceAlias}}' -ResetServerAddresses | Out-Default
+
+# enable DHCP for the address families of the removed addresses only
+foreach ( $AddressFamily in $AddressFamilies ) {
+    Set-NetIPInterface -InterfaceAlias '{{.InterfaceAlias}}' -AddressFamily $AddressFamily -Dhcp 'Enabled' | Out-Default
+}
+`)
+
+//------------------------------------------------------------------------------
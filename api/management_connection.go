@@ -0,0 +1,124 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type ManagementConnection struct {
+    // the host interface carrying the provider's connection to the hyperv-server
+    //     for "ssh", this is the interface with the server address of the ssh session
+    //     for "local", this is the interface with the default route
+    IPAddress                      string
+    InterfaceAlias                 string
+    InterfaceIndex                 int
+
+    // the physical network adapter carrying the connection
+    //     when the interface is a management-os adapter on an "external" switch, this is the switch's network adapter
+    NetAdapterName                 string
+    NetAdapterInterfaceDescription string
+    VSwitchName                    string   // "" when the interface is not a management-os adapter
+}
+
+//------------------------------------------------------------------------------
+
+func (c *HypervClient) ReadManagementConnection() (managementConnection *ManagementConnection, err error) {
+    return readManagementConnection(c)
+}
+
+//------------------------------------------------------------------------------
+
+func readManagementConnection(c *HypervClient) (managementConnection *ManagementConnection, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readManagementConnectionScript, nil, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementConnection()] cannot read management connection\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementConnection()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementConnection()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementConnection()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readManagementConnection()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to managementConnection
+    managementConnection = new(ManagementConnection)
+    err = json.Unmarshal(stdout.Bytes(), managementConnection)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementConnection()] cannot convert json to 'managementConnection'\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementConnection()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readManagementConnection()] read management connection %q\n", managementConnection.InterfaceAlias)
+    return managementConnection, nil
+}
+
+var readManagementConnectionScript = script.New("readManagementConnection", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+if ( $env:SSH_CONNECTION ) {
+    # "<client-address> <client-port> <server-address> <server-port>"
+    $ipAddress = ( $env:SSH_CONNECTION -split ' ' )[2]
+    $NetIPAddressObject = Get-NetIPAddress -IPAddress $ipAddress -ErrorAction 'Ignore' | Select-Object -First 1
+} else {
+    $NetRouteObject = Get-NetRoute -DestinationPrefix '0.0.0.0/0' -ErrorAction 'Ignore' | Sort-Object { $_.RouteMetric + $_.InterfaceMetric } | Select-Object -First 1
+    if ( $NetRouteObject ) {
+        $NetIPAddressObject = Get-NetIPAddress -InterfaceIndex $NetRouteObject.InterfaceIndex -AddressFamily 'IPv4' -ErrorAction 'Ignore' | Select-Object -First 1
+    }
+}
+if ( -not $NetIPAddressObject ) {
+    throw "cannot find management connection"
+}
+
+$NetAdapterObject = Get-NetAdapter -InterfaceIndex $NetIPAddressObject.InterfaceIndex
+
+$ManagementConnection = @{
+    IPAddress      = $NetIPAddressObject.IPAddress
+    InterfaceAlias = $NetAdapterObject.Name
+    InterfaceIndex = $NetAdapterObject.InterfaceIndex
+    VSwitchName    = ''
+}
+
+$VMNetworkAdapterObject = Get-VMNetworkAdapter -ManagementOS -ErrorAction 'Ignore' | Where-Object { $_.DeviceId -eq $NetAdapterObject.DeviceID } | Select-Object -First 1
+if ( $VMNetworkAdapterObject ) {
+    $VMSwitchObject = Get-VMSwitch -Name $VMNetworkAdapterObject.SwitchName
+    $ManagementConnection.VSwitchName = $VMSwitchObject.Name
+    if ( $VMSwitchObject.NetAdapterInterfaceDescription ) {
+        $ManagementConnection.NetAdapterName                 = $( Get-NetAdapter -InterfaceDescription $VMSwitchObject.NetAdapterInterfaceDescription ).Name
+        $ManagementConnection.NetAdapterInterfaceDescription = $VMSwitchObject.NetAdapterInterfaceDescription
+    }
+} else {
+    $ManagementConnection.NetAdapterName                 = $NetAdapterObject.Name
+    $ManagementConnection.NetAdapterInterfaceDescription = $NetAdapterObject.InterfaceDescription
+}
+
+Write-Output $( ConvertTo-Json -InputObject $ManagementConnection )
+`)
+
+//------------------------------------------------------------------------------
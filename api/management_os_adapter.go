@@ -0,0 +1,424 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type ManagementOSAdapter struct {
+    Name                           string   // required
+    SwitchName                     string   // required
+    VlanId                         int      // 0 (default) is untagged
+    MinimumBandwidthWeight         int      // 0 (default) is not configured, requires a vswitch with weight-based bandwidth reservation
+    MacAddress                     string   // "" (default) is a dynamic mac-address
+
+    // computed
+    InterfaceIndex                 int      // the index of the host network interface for the adapter
+    InterfaceAlias                 string   // the name of the host network interface for the adapter, "vEthernet (<name>)" by default
+}
+
+//------------------------------------------------------------------------------
+
+func (c *HypervClient) CreateManagementOSAdapter(moaProperties *ManagementOSAdapter) error {
+    if moaProperties.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateManagementOSAdapter(moaProperties)] missing 'moaProperties.Name'")
+    }
+    if moaProperties.SwitchName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateManagementOSAdapter(moaProperties)] missing 'moaProperties.SwitchName'")
+    }
+
+    return createManagementOSAdapter(c, moaProperties)
+}
+
+func (c *HypervClient) ReadManagementOSAdapter(moa *ManagementOSAdapter) (managementOSAdapter *ManagementOSAdapter, err error) {
+    if moa.Name == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/moa.Read()] missing 'moa.Name'")
+    }
+
+    return readManagementOSAdapter(c, moa)
+}
+
+func (c *HypervClient) UpdateManagementOSAdapter(moa *ManagementOSAdapter, moaProperties *ManagementOSAdapter) error {
+    if moa.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/moa.Update(moaProperties)] missing 'moa.Name'")
+    }
+
+    return updateManagementOSAdapter(c, moa, moaProperties)
+}
+
+func (c *HypervClient) DeleteManagementOSAdapter(moa *ManagementOSAdapter) error {
+    if moa.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/moa.Delete()] missing 'moa.Name'")
+    }
+
+    return deleteManagementOSAdapter(c, moa)
+}
+
+func (c *HypervClient) ReadManagementOSAdapters() (managementOSAdapters []ManagementOSAdapter, err error) {
+    return readManagementOSAdapters(c)
+}
+
+//------------------------------------------------------------------------------
+
+func createManagementOSAdapter(c *HypervClient, moaProperties *ManagementOSAdapter) error {
+    // convert moaProperties to JSON
+    moaPropertiesJSON, err := json.Marshal(moaProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createManagementOSAdapter()] cannot cannot convert 'moaProperties' to json for %q\n", moaProperties.Name)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, createManagementOSAdapterScript, createManagementOSAdapterArguments{
+        MOAPropertiesJSON: string(moaPropertiesJSON),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createManagementOSAdapter()] cannot create management-os adapter %q\n", moaProperties.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createManagementOSAdapter()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createManagementOSAdapter()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createManagementOSAdapter()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/createManagementOSAdapter()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/createManagementOSAdapter()] created management-os adapter %q\n", moaProperties.Name)
+    return nil
+}
+
+type createManagementOSAdapterArguments struct{
+    MOAPropertiesJSON string
+}
+
+var createManagementOSAdapterScript = script.New("createManagementOSAdapter", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$moaProperties = $( ConvertFrom-Json -InputObject '{{.MOAPropertiesJSON}}' )
+
+$VMNetworkAdapterObject = Get-VMNetworkAdapter -ManagementOS -Name $moaProperties.Name -ErrorAction 'Ignore'
+if ( $VMNetworkAdapterObject ) {
+    throw "management-os adapter '$( $moaProperties.Name )' already exists"
+}
+
+$VMSwitchObject = Get-VMSwitch -Name $moaProperties.SwitchName -ErrorAction 'Ignore'
+if ( -not $VMSwitchObject ) {
+    throw "cannot find vswitch '$( $moaProperties.SwitchName )'"
+}
+
+$arguments = @{
+    ManagementOS = $true
+    Name         = $moaProperties.Name
+    SwitchName   = $moaProperties.SwitchName
+}
+
+if ( $moaProperties.MacAddress ) {
+    $arguments.StaticMacAddress = $moaProperties.MacAddress -replace '[-:\.]', ''
+}
+
+Add-VMNetworkAdapter @arguments | Out-Default
+
+if ( $moaProperties.VlanId -gt 0 ) {
+    Set-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $moaProperties.Name -Access -VlanId $moaProperties.VlanId | Out-Default
+}
+
+if ( $moaProperties.MinimumBandwidthWeight -gt 0 ) {
+    Set-VMNetworkAdapter -ManagementOS -Name $moaProperties.Name -MinimumBandwidthWeight $moaProperties.MinimumBandwidthWeight | Out-Default
+}
+`)
+
+//------------------------------------------------------------------------------
+
+func readManagementOSAdapter(c *HypervClient, moa *ManagementOSAdapter) (managementOSAdapter *ManagementOSAdapter, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readManagementOSAdapterScript, readManagementOSAdapterArguments{
+        Name: moa.Name,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementOSAdapter()] cannot read management-os adapter %q\n", moa.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementOSAdapter()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementOSAdapter()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementOSAdapter()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readManagementOSAdapter()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to managementOSAdapter
+    managementOSAdapter = new(ManagementOSAdapter)
+    err = json.Unmarshal(stdout.Bytes(), managementOSAdapter)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementOSAdapter()] cannot convert json to 'managementOSAdapter' for %q\n", moa.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementOSAdapter()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readManagementOSAdapter()] read management-os adapter %q\n", moa.Name)
+    return managementOSAdapter, nil
+}
+
+type readManagementOSAdapterArguments struct{
+    Name string
+}
+
+var readManagementOSAdapterScript = script.New("readManagementOSAdapter", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$VMNetworkAdapterObject = Get-VMNetworkAdapter -ManagementOS -Name '{{.Name}}' -ErrorAction 'Ignore'
+if ( -not $VMNetworkAdapterObject ) {
+    throw "cannot find management-os adapter '{{.Name}}'"
+}
+
+$VMNetworkAdapterVlanObject = Get-VMNetworkAdapterVlan -VMNetworkAdapter $VMNetworkAdapterObject
+
+$ManagementOSAdapter = @{
+    Name                   = $VMNetworkAdapterObject.Name
+    SwitchName             = $VMNetworkAdapterObject.SwitchName
+    VlanId                 = [int]$VMNetworkAdapterVlanObject.AccessVlanId
+    MinimumBandwidthWeight = [int]$VMNetworkAdapterObject.BandwidthSetting.MinimumBandwidthWeight
+    MacAddress             = $VMNetworkAdapterObject.MacAddress
+}
+
+$NetAdapterObject = Get-NetAdapter | Where-Object { $_.DeviceID -eq $VMNetworkAdapterObject.DeviceId }
+if ( $NetAdapterObject ) {
+    $ManagementOSAdapter.InterfaceIndex = $NetAdapterObject.InterfaceIndex
+    $ManagementOSAdapter.InterfaceAlias = $NetAdapterObject.Name
+}
+
+Write-Output $( ConvertTo-Json -InputObject $ManagementOSAdapter )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateManagementOSAdapter(c *HypervClient, moa *ManagementOSAdapter, moaProperties *ManagementOSAdapter) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // convert moaProperties to JSON
+    moaPropertiesJSON, err := json.Marshal(moaProperties)
+    if err != nil {
+        return err
+    }
+
+    // run script
+    err = runner.Run(c, updateManagementOSAdapterScript, updateManagementOSAdapterArguments{
+        Name:              moa.Name,
+        MOAPropertiesJSON: string(moaPropertiesJSON),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateManagementOSAdapter()] cannot update management-os adapter %q\n", moa.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateManagementOSAdapter()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateManagementOSAdapter()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateManagementOSAdapter()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/updateManagementOSAdapter()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/updateManagementOSAdapter()] updated management-os adapter %q\n", moa.Name)
+    return nil
+}
+
+type updateManagementOSAdapterArguments struct{
+    Name              string
+    MOAPropertiesJSON string
+}
+
+var updateManagementOSAdapterScript = script.New("updateManagementOSAdapter", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$VMNetworkAdapterObject = Get-VMNetworkAdapter -ManagementOS -Name '{{.Name}}' -ErrorAction 'Ignore'
+if ( -not $VMNetworkAdapterObject ) {
+    throw "cannot find management-os adapter '{{.Name}}'"
+}
+
+$moaProperties = $( ConvertFrom-Json -InputObject '{{.MOAPropertiesJSON}}' )
+
+if ( $moaProperties.SwitchName -and ( $moaProperties.SwitchName -ne $VMNetworkAdapterObject.SwitchName ) ) {
+    Connect-VMNetworkAdapter -ManagementOS -Name '{{.Name}}' -SwitchName $moaProperties.SwitchName | Out-Default
+}
+
+if ( $moaProperties.VlanId -gt 0 ) {
+    Set-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName '{{.Name}}' -Access -VlanId $moaProperties.VlanId | Out-Default
+} else {
+    Set-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName '{{.Name}}' -Untagged | Out-Default
+}
+
+$arguments = @{
+    ManagementOS = $true
+    Name         = '{{.Name}}'
+}
+
+if ( ( $moaProperties.MinimumBandwidthWeight -gt 0 ) -or ( $VMNetworkAdapterObject.BandwidthSetting.MinimumBandwidthWeight -gt 0 ) ) {
+    $arguments.MinimumBandwidthWeight = $moaProperties.MinimumBandwidthWeight
+}
+
+if ( $moaProperties.MacAddress ) {   # only when changed, the mac-address is left untouched otherwise
+    $arguments.StaticMacAddress = $moaProperties.MacAddress -replace '[-:\.]', ''
+}
+
+Set-VMNetworkAdapter @arguments | Out-Default
+
+if ( $moaProperties.Name -and ( $moaProperties.Name -ne '{{.Name}}' ) ) {
+    Rename-VMNetworkAdapter -ManagementOS -Name '{{.Name}}' -NewName $moaProperties.Name | Out-Default
+}
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteManagementOSAdapter(c *HypervClient, moa *ManagementOSAdapter) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err := runner.Run(c, deleteManagementOSAdapterScript, deleteManagementOSAdapterArguments{
+        Name: moa.Name,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteManagementOSAdapter()] cannot delete management-os adapter %q\n", moa.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteManagementOSAdapter()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteManagementOSAdapter()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteManagementOSAdapter()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/deleteManagementOSAdapter()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/deleteManagementOSAdapter()] deleted management-os adapter %q\n", moa.Name)
+    return nil
+}
+
+type deleteManagementOSAdapterArguments struct{
+    Name string
+}
+
+var deleteManagementOSAdapterScript = script.New("deleteManagementOSAdapter", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$VMNetworkAdapterObject = Get-VMNetworkAdapter -ManagementOS -Name '{{.Name}}' -ErrorAction 'Ignore'
+if ( -not $VMNetworkAdapterObject ) {
+    throw "cannot find management-os adapter '{{.Name}}'"
+}
+
+Remove-VMNetworkAdapter -ManagementOS -Name '{{.Name}}' | Out-Default
+`)
+
+//------------------------------------------------------------------------------
+
+func readManagementOSAdapters(c *HypervClient) (managementOSAdapters []ManagementOSAdapter, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readManagementOSAdaptersScript, nil, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementOSAdapters()] cannot read management-os adapters\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementOSAdapters()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementOSAdapters()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementOSAdapters()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readManagementOSAdapters()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to managementOSAdapters
+    err = json.Unmarshal(stdout.Bytes(), &managementOSAdapters)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementOSAdapters()] cannot convert json to 'managementOSAdapters'\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readManagementOSAdapters()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readManagementOSAdapters()] read %d management-os adapters\n", len(managementOSAdapters))
+    return managementOSAdapters, nil
+}
+
+var readManagementOSAdaptersScript = script.New("readManagementOSAdapters", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$NetAdapterObjects = @( Get-NetAdapter )
+
+$ManagementOSAdapters = @( Get-VMNetworkAdapter -ManagementOS -ErrorAction 'Ignore' | ForEach-Object {
+    $VMNetworkAdapterObject = $_
+
+    $VMNetworkAdapterVlanObject = Get-VMNetworkAdapterVlan -VMNetworkAdapter $VMNetworkAdapterObject
+
+    $ManagementOSAdapter = @{
+        Name                   = $VMNetworkAdapterObject.Name
+        SwitchName             = $VMNetworkAdapterObject.SwitchName
+        VlanId                 = [int]$VMNetworkAdapterVlanObject.AccessVlanId
+        MinimumBandwidthWeight = [int]$VMNetworkAdapterObject.BandwidthSetting.MinimumBandwidthWeight
+        MacAddress             = $VMNetworkAdapterObject.MacAddress
+    }
+
+    $NetAdapterObject = $NetAdapterObjects | Where-Object { $_.DeviceID -eq $VMNetworkAdapterObject.DeviceId } | Select-Object -First 1
+    if ( $NetAdapterObject ) {
+        $ManagementOSAdapter.InterfaceIndex = $NetAdapterObject.InterfaceIndex
+        $ManagementOSAdapter.InterfaceAlias = $NetAdapterObject.Name
+    }
+
+    $ManagementOSAdapter
+} )
+
+Write-Output $( ConvertTo-Json -InputObject $ManagementOSAdapters )
+`)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,279 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type Nat struct {
+    Name                             string   // required
+    InternalIPInterfaceAddressPrefix string   // required, f.i. "192.168.100.0/24"
+
+    // computed
+    Active                           bool
+}
+
+//------------------------------------------------------------------------------
+
+func (c *HypervClient) CreateNat(natProperties *Nat) error {
+    if natProperties.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateNat(natProperties)] missing 'natProperties.Name'")
+    }
+    if natProperties.InternalIPInterfaceAddressPrefix == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateNat(natProperties)] missing 'natProperties.InternalIPInterfaceAddressPrefix'")
+    }
+
+    return createNat(c, natProperties)
+}
+
+func (c *HypervClient) ReadNat(nat *Nat) (netnat *Nat, err error) {
+    if nat.Name == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/nat.Read()] missing 'nat.Name'")
+    }
+
+    return readNat(c, nat)
+}
+
+func (c *HypervClient) DeleteNat(nat *Nat) error {
+    if nat.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/nat.Delete()] missing 'nat.Name'")
+    }
+
+    return deleteNat(c, nat)
+}
+
+func (c *HypervClient) ReadNats() (nats []Nat, err error) {
+    return readNats(c)
+}
+
+//------------------------------------------------------------------------------
+
+func createNat(c *HypervClient, natProperties *Nat) error {
+    // convert natProperties to JSON
+    natPropertiesJSON, err := json.Marshal(natProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createNat()] cannot cannot convert 'natProperties' to json for %q\n", natProperties.Name)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, createNatScript, createNatArguments{
+        NatPropertiesJSON: string(natPropertiesJSON),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createNat()] cannot create nat %q\n", natProperties.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createNat()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createNat()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createNat()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/createNat()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/createNat()] created nat %q\n", natProperties.Name)
+    return nil
+}
+
+type createNatArguments struct{
+    NatPropertiesJSON string
+}
+
+var createNatScript = script.New("createNat", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$natProperties = $( ConvertFrom-Json -InputObject '{{.NatPropertiesJSON}}' )
+
+$NetNatObject = Get-NetNat -Name $natProperties.Name -ErrorAction 'Ignore'
+if ( $NetNatObject ) {
+    throw "nat '$( $natProperties.Name )' already exists"
+}
+
+New-NetNat -Name $natProperties.Name -InternalIPInterfaceAddressPrefix $natProperties.InternalIPInterfaceAddressPrefix | Out-Null
+`)
+
+//------------------------------------------------------------------------------
+
+func readNat(c *HypervClient, nat *Nat) (netnat *Nat, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readNatScript, readNatArguments{
+        Name: nat.Name,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNat()] cannot read nat %q\n", nat.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNat()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNat()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNat()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readNat()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to netnat
+    netnat = new(Nat)
+    err = json.Unmarshal(stdout.Bytes(), netnat)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNat()] cannot convert json to 'netnat' for %q\n", nat.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNat()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readNat()] read nat %q\n", nat.Name)
+    return netnat, nil
+}
+
+type readNatArguments struct{
+    Name string
+}
+
+var readNatScript = script.New("readNat", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$NetNatObject = Get-NetNat -Name '{{.Name}}' -ErrorAction 'Ignore'
+if ( -not $NetNatObject ) {
+    throw "cannot find nat '{{.Name}}'"
+}
+
+$Nat = @{
+    Name                             = $NetNatObject.Name
+    InternalIPInterfaceAddressPrefix = $NetNatObject.InternalIPInterfaceAddressPrefix
+    Active                           = $NetNatObject.Active
+}
+
+Write-Output $( ConvertTo-Json -InputObject $Nat )
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteNat(c *HypervClient, nat *Nat) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err := runner.Run(c, deleteNatScript, deleteNatArguments{
+        Name: nat.Name,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteNat()] cannot delete nat %q\n", nat.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteNat()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteNat()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteNat()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/deleteNat()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/deleteNat()] deleted nat %q\n", nat.Name)
+    return nil
+}
+
+type deleteNatArguments struct{
+    Name string
+}
+
+var deleteNatScript = script.New("deleteNat", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$NetNatObject = Get-NetNat -Name '{{.Name}}' -ErrorAction 'Ignore'
+if ( -not $NetNatObject ) {
+    throw "cannot find nat '{{.Name}}'"
+}
+
+Remove-NetNat -Name '{{.Name}}' -Confirm:$false | Out-Default
+`)
+
+//------------------------------------------------------------------------------
+
+func readNats(c *HypervClient) (nats []Nat, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readNatsScript, nil, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNats()] cannot read nats\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNats()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNats()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNats()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readNats()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to nats
+    err = json.Unmarshal(stdout.Bytes(), &nats)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNats()] cannot convert json to 'nats'\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNats()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readNats()] read %d nats\n", len(nats))
+    return nats, nil
+}
+
+var readNatsScript = script.New("readNats", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$Nats = @( Get-NetNat -ErrorAction 'Ignore' | ForEach-Object {
+    @{
+        Name                             = $_.Name
+        InternalIPInterfaceAddressPrefix = $_.InternalIPInterfaceAddressPrefix
+        Active                           = $_.Active
+    }
+} )
+
+Write-Output $( ConvertTo-Json -InputObject $Nats )
+`)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,345 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type NatStaticMapping struct {
+    // a static mapping is identified by its nat, protocol, external ip-address and external port
+    NatName                        string   // required
+    Protocol                       string   // required, "tcp" or "udp"
+    ExternalIPAddress              string   // required, "0.0.0.0" for all external ip-addresses
+    ExternalPort                   int      // required
+    InternalIPAddress              string   // required
+    InternalPort                   int      // required
+
+    // computed
+    StaticMappingID                int
+    Active                         bool
+}
+
+//------------------------------------------------------------------------------
+
+func (c *HypervClient) CreateNatStaticMapping(nsmProperties *NatStaticMapping) error {
+    if nsmProperties.NatName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateNatStaticMapping(nsmProperties)] missing 'nsmProperties.NatName'")
+    }
+    if nsmProperties.Protocol == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateNatStaticMapping(nsmProperties)] missing 'nsmProperties.Protocol'")
+    }
+    if nsmProperties.ExternalIPAddress == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateNatStaticMapping(nsmProperties)] missing 'nsmProperties.ExternalIPAddress'")
+    }
+    if nsmProperties.InternalIPAddress == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateNatStaticMapping(nsmProperties)] missing 'nsmProperties.InternalIPAddress'")
+    }
+
+    return createNatStaticMapping(c, nsmProperties)
+}
+
+func (c *HypervClient) ReadNatStaticMapping(nsm *NatStaticMapping) (natStaticMapping *NatStaticMapping, err error) {
+    if nsm.NatName == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/nsm.Read()] missing 'nsm.NatName'")
+    }
+    if nsm.Protocol == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/nsm.Read()] missing 'nsm.Protocol'")
+    }
+    if nsm.ExternalIPAddress == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/nsm.Read()] missing 'nsm.ExternalIPAddress'")
+    }
+
+    return readNatStaticMapping(c, nsm)
+}
+
+func (c *HypervClient) DeleteNatStaticMapping(nsm *NatStaticMapping) error {
+    if nsm.NatName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/nsm.Delete()] missing 'nsm.NatName'")
+    }
+    if nsm.Protocol == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/nsm.Delete()] missing 'nsm.Protocol'")
+    }
+    if nsm.ExternalIPAddress == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/nsm.Delete()] missing 'nsm.ExternalIPAddress'")
+    }
+
+    return deleteNatStaticMapping(c, nsm)
+}
+
+func (c *HypervClient) ReadNatStaticMappings() (natStaticMappings []NatStaticMapping, err error) {
+    return readNatStaticMappings(c)
+}
+
+//------------------------------------------------------------------------------
+
+func createNatStaticMapping(c *HypervClient, nsmProperties *NatStaticMapping) error {
+    // convert nsmProperties to JSON
+    nsmPropertiesJSON, err := json.Marshal(nsmProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createNatStaticMapping()] cannot cannot convert 'nsmProperties' to json for %q\n", nsmProperties.NatName)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, createNatStaticMappingScript, createNatStaticMappingArguments{
+        NSMPropertiesJSON: string(nsmPropertiesJSON),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createNatStaticMapping()] cannot create static mapping %s %s:%d for nat %q\n", nsmProperties.Protocol, nsmProperties.ExternalIPAddress, nsmProperties.ExternalPort, nsmProperties.NatName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createNatStaticMapping()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createNatStaticMapping()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createNatStaticMapping()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/createNatStaticMapping()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/createNatStaticMapping()] created static mapping %s %s:%d for nat %q\n", nsmProperties.Protocol, nsmProperties.ExternalIPAddress, nsmProperties.ExternalPort, nsmProperties.NatName)
+    return nil
+}
+
+type createNatStaticMappingArguments struct{
+    NSMPropertiesJSON string
+}
+
+var createNatStaticMappingScript = script.New("createNatStaticMapping", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$nsmProperties = $( ConvertFrom-Json -InputObject '{{.NSMPropertiesJSON}}' )
+
+$NetNatObject = Get-NetNat -Name $nsmProperties.NatName -ErrorAction 'Ignore'
+if ( -not $NetNatObject ) {
+    throw "cannot find nat '$( $nsmProperties.NatName )'"
+}
+
+$NetNatStaticMappingObject = Get-NetNatStaticMapping -NatName $nsmProperties.NatName -ErrorAction 'Ignore' | Where-Object {
+    ( $_.Protocol -eq $nsmProperties.Protocol ) -and ( $_.ExternalIPAddress -eq $nsmProperties.ExternalIPAddress ) -and ( $_.ExternalPort -eq $nsmProperties.ExternalPort )
+}
+if ( $NetNatStaticMappingObject ) {
+    throw "static mapping '$( $nsmProperties.Protocol ) $( $nsmProperties.ExternalIPAddress ):$( $nsmProperties.ExternalPort )' for nat '$( $nsmProperties.NatName )' already exists"
+}
+
+$arguments = @{
+    NatName           = $nsmProperties.NatName
+    Protocol          = $nsmProperties.Protocol.ToUpper()
+    ExternalIPAddress = $nsmProperties.ExternalIPAddress
+    ExternalPort      = $nsmProperties.ExternalPort
+    InternalIPAddress = $nsmProperties.InternalIPAddress
+    InternalPort      = $nsmProperties.InternalPort
+}
+
+Add-NetNatStaticMapping @arguments | Out-Null
+`)
+
+//------------------------------------------------------------------------------
+
+func readNatStaticMapping(c *HypervClient, nsm *NatStaticMapping) (natStaticMapping *NatStaticMapping, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readNatStaticMappingScript, readNatStaticMappingArguments{
+        NatName:           nsm.NatName,
+        Protocol:          nsm.Protocol,
+        ExternalIPAddress: nsm.ExternalIPAddress,
+        ExternalPort:      nsm.ExternalPort,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNatStaticMapping()] cannot read static mapping %s %s:%d for nat %q\n", nsm.Protocol, nsm.ExternalIPAddress, nsm.ExternalPort, nsm.NatName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNatStaticMapping()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNatStaticMapping()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNatStaticMapping()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readNatStaticMapping()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to natStaticMapping
+    natStaticMapping = new(NatStaticMapping)
+    err = json.Unmarshal(stdout.Bytes(), natStaticMapping)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNatStaticMapping()] cannot convert json to 'natStaticMapping' for %q\n", nsm.NatName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNatStaticMapping()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readNatStaticMapping()] read static mapping %s %s:%d for nat %q\n", nsm.Protocol, nsm.ExternalIPAddress, nsm.ExternalPort, nsm.NatName)
+    return natStaticMapping, nil
+}
+
+type readNatStaticMappingArguments struct{
+    NatName           string
+    Protocol          string
+    ExternalIPAddress string
+    ExternalPort      int
+}
+
+var readNatStaticMappingScript = script.New("readNatStaticMapping", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$NetNatStaticMappingObject = Get-NetNatStaticMapping -NatName '{{.NatName}}' -ErrorAction 'Ignore' | Where-Object {
+    ( $_.Protocol -eq '{{.Protocol}}' ) -and ( $_.ExternalIPAddress -eq '{{.ExternalIPAddress}}' ) -and ( $_.ExternalPort -eq {{.ExternalPort}} )
+} | Select-Object -First 1
+if ( -not $NetNatStaticMappingObject ) {
+    throw "cannot find static mapping '{{.Protocol}} {{.ExternalIPAddress}}:{{.ExternalPort}}' for nat '{{.NatName}}'"
+}
+
+$NatStaticMapping = @{
+    NatName           = $NetNatStaticMappingObject.NatName
+    Protocol          = $( [string]$NetNatStaticMappingObject.Protocol ).ToLower()
+    ExternalIPAddress = $NetNatStaticMappingObject.ExternalIPAddress
+    ExternalPort      = [int]$NetNatStaticMappingObject.ExternalPort
+    InternalIPAddress = $NetNatStaticMappingObject.InternalIPAddress
+    InternalPort      = [int]$NetNatStaticMappingObject.InternalPort
+    StaticMappingID   = [int]$NetNatStaticMappingObject.StaticMappingID
+    Active            = $NetNatStaticMappingObject.Active
+}
+
+Write-Output $( ConvertTo-Json -InputObject $NatStaticMapping )
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteNatStaticMapping(c *HypervClient, nsm *NatStaticMapping) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err := runner.Run(c, deleteNatStaticMappingScript, deleteNatStaticMappingArguments{
+        NatName:           nsm.NatName,
+        Protocol:          nsm.Protocol,
+        ExternalIPAddress: nsm.ExternalIPAddress,
+        ExternalPort:      nsm.ExternalPort,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteNatStaticMapping()] cannot delete static mapping %s %s:%d for nat %q\n", nsm.Protocol, nsm.ExternalIPAddress, nsm.ExternalPort, nsm.NatName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteNatStaticMapping()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteNatStaticMapping()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteNatStaticMapping()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/deleteNatStaticMapping()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/deleteNatStaticMapping()] deleted static mapping %s %s:%d for nat %q\n", nsm.Protocol, nsm.ExternalIPAddress, nsm.ExternalPort, nsm.NatName)
+    return nil
+}
+
+type deleteNatStaticMappingArguments struct{
+    NatName           string
+    Protocol          string
+    ExternalIPAddress string
+    ExternalPort      int
+}
+
+var deleteNatStaticMappingScript = script.New("deleteNatStaticMapping", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$NetNatStaticMappingObject = Get-NetNatStaticMapping -NatName '{{.NatName}}' -ErrorAction 'Ignore' | Where-Object {
+    ( $_.Protocol -eq '{{.Protocol}}' ) -and ( $_.ExternalIPAddress -eq '{{.ExternalIPAddress}}' ) -and ( $_.ExternalPort -eq {{.ExternalPort}} )
+}
+if ( -not $NetNatStaticMappingObject ) {
+    throw "cannot find static mapping '{{.Protocol}} {{.ExternalIPAddress}}:{{.ExternalPort}}' for nat '{{.NatName}}'"
+}
+
+$NetNatStaticMappingObject | Remove-NetNatStaticMapping -Confirm:$false | Out-Default
+`)
+
+//------------------------------------------------------------------------------
+
+func readNatStaticMappings(c *HypervClient) (natStaticMappings []NatStaticMapping, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readNatStaticMappingsScript, nil, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNatStaticMappings()] cannot read nat static mappings\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNatStaticMappings()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNatStaticMappings()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNatStaticMappings()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readNatStaticMappings()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to natStaticMappings
+    err = json.Unmarshal(stdout.Bytes(), &natStaticMappings)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNatStaticMappings()] cannot convert json to 'natStaticMappings'\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNatStaticMappings()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readNatStaticMappings()] read %d nat static mappings\n", len(natStaticMappings))
+    return natStaticMappings, nil
+}
+
+var readNatStaticMappingsScript = script.New("readNatStaticMappings", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$NatStaticMappings = @( Get-NetNatStaticMapping -ErrorAction 'Ignore' | ForEach-Object {
+    @{
+        NatName           = $_.NatName
+        Protocol          = $( [string]$_.Protocol ).ToLower()
+        ExternalIPAddress = $_.ExternalIPAddress
+        ExternalPort      = [int]$_.ExternalPort
+        InternalIPAddress = $_.InternalIPAddress
+        InternalPort      = [int]$_.InternalPort
+        StaticMappingID   = [int]$_.StaticMappingID
+        Active            = $_.Active
+    }
+} )
+
+Write-Output $( ConvertTo-Json -InputObject $NatStaticMappings )
+`)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,104 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type NetAdapter struct {
+    Name                           string
+    InterfaceDescription           string
+    InterfaceIndex                 int
+    MacAddress                     string
+    LinkSpeed                      string   // f.i. "1 Gbps"
+    Status                         string   // "up", "disconnected", "disabled", ...
+    VlanID                         int
+    Physical                       bool     // true for hardware adapters, false for virtual adapters
+    VSwitchName                    string   // the name of the vswitch the adapter is bound to, "" when not bound
+}
+
+//------------------------------------------------------------------------------
+
+func (c *HypervClient) ReadNetAdapters() (netAdapters []NetAdapter, err error) {
+    return readNetAdapters(c)
+}
+
+//------------------------------------------------------------------------------
+
+func readNetAdapters(c *HypervClient) (netAdapters []NetAdapter, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readNetAdaptersScript, nil, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNetAdapters()] cannot read net adapters\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNetAdapters()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNetAdapters()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNetAdapters()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readNetAdapters()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to netAdapters
+    err = json.Unmarshal(stdout.Bytes(), &netAdapters)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNetAdapters()] cannot convert json to 'netAdapters'\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readNetAdapters()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readNetAdapters()] read %d net adapters\n", len(netAdapters))
+    return netAdapters, nil
+}
+
+var readNetAdaptersScript = script.New("readNetAdapters", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$VMSwitchObjects = @( Get-VMSwitch -SwitchType 'External' -ErrorAction 'Ignore' )
+
+$NetAdapters = @( Get-NetAdapter | ForEach-Object {
+    $NetAdapterObject = $_
+
+    $VMSwitchObject = $VMSwitchObjects | Where-Object { $_.NetAdapterInterfaceDescription -eq $NetAdapterObject.InterfaceDescription } | Select-Object -First 1
+
+    @{
+        Name                 = $NetAdapterObject.Name
+        InterfaceDescription = $NetAdapterObject.InterfaceDescription
+        InterfaceIndex       = $NetAdapterObject.InterfaceIndex
+        MacAddress           = $NetAdapterObject.MacAddress
+        LinkSpeed            = $NetAdapterObject.LinkSpeed
+        Status               = $( [string]$NetAdapterObject.Status ).ToLower()
+        VlanID               = [int]$NetAdapterObject.VlanID
+        Physical             = [bool]$NetAdapterObject.HardwareInterface
+        VSwitchName          = $( if ( $VMSwitchObject ) { $VMSwitchObject.Name } else { '' } )
+    }
+} )
+
+Write-Output $( ConvertTo-Json -InputObject $NetAdapters )
+`)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,55 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "encoding/json"
+    "strings"
+)
+
+//------------------------------------------------------------------------------
+
+// hyperv objects don't support tags, the tags are serialized in a machine-readable line at the end of the 'Notes' of the object
+//     <human-written notes>
+//     #terraform-provider-hyperv:tags {"environment":"lab","owner":"me"}
+const notesTagsMarker = "#terraform-provider-hyperv:tags "
+
+// JoinNotes returns the 'Notes' for an object, combining the human-written notes and the tags
+func JoinNotes(notes string, tags map[string]string) string {
+    if len(tags) == 0 {
+        return notes
+    }
+
+    tagsJSON, _ := json.Marshal(tags)   // map keys are sorted, so the result is stable
+    if notes == "" {
+        return notesTagsMarker + string(tagsJSON)
+    }
+    return notes + "\n" + notesTagsMarker + string(tagsJSON)
+}
+
+// SplitNotes returns the human-written notes and the tags from the 'Notes' of an object
+// when the tags cannot be parsed, the 'Notes' are returned as human-written notes
+func SplitNotes(s string) (notes string, tags map[string]string) {
+    i := strings.LastIndex(s, notesTagsMarker)
+    if i < 0 || ( i > 0 && s[i-1] != '\n' ) {
+        return s, nil
+    }
+
+    err := json.Unmarshal([]byte(strings.TrimSpace(s[i+len(notesTagsMarker):])), &tags)
+    if err != nil {
+        return s, nil
+    }
+
+    return strings.TrimRight(s[:i], "\r\n"), tags
+}
+
+// escapeSingleQuotes returns a string that can be used in a single-quoted powershell string, f.i. the JSON of properties with notes and tags
+func escapeSingleQuotes(s string) string {
+    return strings.ReplaceAll(s, "'", "''")
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,90 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "reflect"
+    "testing"
+)
+
+//------------------------------------------------------------------------------
+
+func TestJoinNotes(t *testing.T) {
+    tests := []struct {
+        notes    string
+        tags     map[string]string
+        expected string
+    }{
+        { "", nil, "" },
+        { "my notes", nil, "my notes" },
+        { "my notes", map[string]string{}, "my notes" },
+        { "", map[string]string{ "owner": "me" }, `#terraform-provider-hyperv:tags {"owner":"me"}` },
+        { "my notes", map[string]string{ "owner": "me", "environment": "lab" }, "my notes\n" + `#terraform-provider-hyperv:tags {"environment":"lab","owner":"me"}` },
+    }
+
+    for _, test := range tests {
+        if actual := JoinNotes(test.notes, test.tags); actual != test.expected {
+            t.Errorf("JoinNotes(%q, %#v): expected %q, got %q", test.notes, test.tags, test.expected, actual)
+        }
+    }
+}
+
+func TestSplitNotes(t *testing.T) {
+    tests := []struct {
+        s             string
+        expectedNotes string
+        expectedTags  map[string]string
+    }{
+        { "", "", nil },
+        { "my notes", "my notes", nil },
+        { `#terraform-provider-hyperv:tags {"owner":"me"}`, "", map[string]string{ "owner": "me" } },
+        { "my notes\n" + `#terraform-provider-hyperv:tags {"environment":"lab","owner":"me"}`, "my notes", map[string]string{ "owner": "me", "environment": "lab" } },
+        { "my notes\r\n" + `#terraform-provider-hyperv:tags {"owner":"me"}` + "\r\n", "my notes", map[string]string{ "owner": "me" } },   // notes edited on the hyperv-server
+        { "my notes " + `#terraform-provider-hyperv:tags {"owner":"me"}`, "my notes " + `#terraform-provider-hyperv:tags {"owner":"me"}`, nil },   // not at the start of a line
+        { "my notes\n" + `#terraform-provider-hyperv:tags {"owner":`, "my notes\n" + `#terraform-provider-hyperv:tags {"owner":`, nil },   // cannot be parsed
+    }
+
+    for _, test := range tests {
+        notes, tags := SplitNotes(test.s)
+        if notes != test.expectedNotes {
+            t.Errorf("SplitNotes(%q): expected notes %q, got %q", test.s, test.expectedNotes, notes)
+        }
+        if !reflect.DeepEqual(tags, test.expectedTags) {
+            t.Errorf("SplitNotes(%q): expected tags %#v, got %#v", test.s, test.expectedTags, tags)
+        }
+    }
+}
+
+func TestEscapeSingleQuotes(t *testing.T) {
+    tests := []struct {
+        s        string
+        expected string
+    }{
+        { "", "" },
+        { `{"owner":"me"}`, `{"owner":"me"}` },
+        { `{"owner":"O'Brien"}`, `{"owner":"O''Brien"}` },
+        { "''", "''''" },
+    }
+
+    for _, test := range tests {
+        if actual := escapeSingleQuotes(test.s); actual != test.expected {
+            t.Errorf("escapeSingleQuotes(%q): expected %q, got %q", test.s, test.expected, actual)
+        }
+    }
+}
+
+func TestSplitNotesJoinNotes(t *testing.T) {
+    notes := "my notes\nsecond line"
+    tags  := map[string]string{ "owner": "me", "ttl": "2019-12-31" }
+
+    actualNotes, actualTags := SplitNotes(JoinNotes(notes, tags))
+    if actualNotes != notes || !reflect.DeepEqual(actualTags, tags) {
+        t.Errorf("SplitNotes(JoinNotes(%q, %#v)): got %q, %#v", notes, tags, actualNotes, actualTags)
+    }
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,404 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type VHD struct {
+    Path                           string   // required, the extension of the file must match the format
+    Format                         string   // "vhd" or "vhdx" - "" (default) is derived from the extension of the path
+    Type                           string   // "fixed", "dynamic" (default) or "differencing"
+    Size                           int64    // required, except for a differencing disk
+    BlockSize                      int64    // 0 (default) is the default block size of the hyperv-server
+    LogicalSectorSize              int      // 512 or 4096 - 0 (default) is the default logical sector size of the hyperv-server
+    PhysicalSectorSize             int      // 512 or 4096 - 0 (default) is the default physical sector size of the hyperv-server
+    ParentPath                     string   // required for a differencing disk
+
+    // computed
+    FileSize                       int64    // the size of the file on the hyperv-server
+    FragmentationPercentage        int
+    Attached                       bool     // true when the disk is attached to a running vm or mounted on the hyperv-server
+}
+
+//------------------------------------------------------------------------------
+
+func (c *HypervClient) CreateVHD(vhdProperties *VHD) error {
+    if vhdProperties.Path == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVHD(vhdProperties)] missing 'vhdProperties.Path'")
+    }
+    if vhdProperties.Type == "differencing" {
+        if vhdProperties.ParentPath == "" {
+            return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVHD(vhdProperties)] missing 'vhdProperties.ParentPath'")
+        }
+    } else {
+        if vhdProperties.Size == 0 {
+            return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVHD(vhdProperties)] missing 'vhdProperties.Size'")
+        }
+    }
+
+    return createVHD(c, vhdProperties)
+}
+
+func (c *HypervClient) ReadVHD(v *VHD) (vhd *VHD, err error) {
+    if v.Path == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/ReadVHD(v)] missing 'v.Path'")
+    }
+
+    return readVHD(c, v)
+}
+
+// UpdateVHD converts the vhd to another format, type or path, and resizes the vhd
+//     converting needs a vhd that isn't attached, it is converted to a temporary file that replaces the original file
+//     resizing an attached vhd needs a vhdx on a scsi controller, shrinking is refused unless allowShrink is true
+func (c *HypervClient) UpdateVHD(v *VHD, vhdProperties *VHD, allowShrink bool) error {
+    if v.Path == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/UpdateVHD(v, vhdProperties, allowShrink)] missing 'v.Path'")
+    }
+    if vhdProperties.Type == "differencing" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/UpdateVHD(v, vhdProperties, allowShrink)] cannot convert to 'vhdProperties.Type' %q", vhdProperties.Type)
+    }
+
+    return updateVHD(c, v, vhdProperties, allowShrink)
+}
+
+func (c *HypervClient) DeleteVHD(v *VHD) error {
+    if v.Path == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/DeleteVHD(v)] missing 'v.Path'")
+    }
+
+    return deleteVHD(c, v)
+}
+
+//------------------------------------------------------------------------------
+
+func createVHD(c *HypervClient, vhdProperties *VHD) error {
+    // convert vhdProperties to JSON
+    vhdPropertiesJSON, err := json.Marshal(vhdProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVHD()] cannot cannot convert 'vhdProperties' to json for %q\n", vhdProperties.Path)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, createVHDScript, createVHDArguments{
+        VHDPropertiesJSON: string(vhdPropertiesJSON),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVHD()] cannot create vhd %q\n", vhdProperties.Path)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVHD()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVHD()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVHD()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/createVHD()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/createVHD()] created vhd %q\n", vhdProperties.Path)
+    return nil
+}
+
+type createVHDArguments struct{
+    VHDPropertiesJSON string
+}
+
+var createVHDScript = script.New("createVHD", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$vhdProperties = $( ConvertFrom-Json -InputObject '{{.VHDPropertiesJSON}}' )
+
+if ( Test-Path -LiteralPath $vhdProperties.Path ) {
+    throw "vhd '$( $vhdProperties.Path )' already exists"
+}
+
+# the format of a vhd is derived from the extension of its path
+$extension = [System.IO.Path]::GetExtension($vhdProperties.Path).TrimStart('.').ToLower()
+if ( $vhdProperties.Format -and ( $vhdProperties.Format -ne $extension ) ) {
+    throw "the extension of vhd '$( $vhdProperties.Path )' doesn't match format '$( $vhdProperties.Format )'"
+}
+
+$arguments = @{
+    Path = $vhdProperties.Path
+}
+switch ( $vhdProperties.Type ) {
+    'fixed' {
+        $arguments.Fixed = $true
+    }
+    'differencing' {
+        $arguments.Differencing = $true
+        $arguments.ParentPath = $vhdProperties.ParentPath
+    }
+    default {
+        $arguments.Dynamic = $true
+    }
+}
+if ( $vhdProperties.Size ) {
+    $arguments.SizeBytes = $vhdProperties.Size
+}
+if ( $vhdProperties.BlockSize ) {
+    $arguments.BlockSizeBytes = $vhdProperties.BlockSize
+}
+if ( $vhdProperties.LogicalSectorSize ) {
+    $arguments.LogicalSectorSizeBytes = $vhdProperties.LogicalSectorSize
+}
+if ( $vhdProperties.PhysicalSectorSize ) {
+    $arguments.PhysicalSectorSizeBytes = $vhdProperties.PhysicalSectorSize
+}
+
+New-VHD @arguments | Out-Null
+`)
+
+//------------------------------------------------------------------------------
+
+func readVHD(c *HypervClient, v *VHD) (vhd *VHD, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readVHDScript, readVHDArguments{
+        Path: v.Path,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVHD()] cannot read vhd %q\n", v.Path)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVHD()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVHD()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVHD()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readVHD()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to vhd
+    vhd = new(VHD)
+    err = json.Unmarshal(stdout.Bytes(), vhd)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVHD()] cannot convert json to 'vhd' for %q\n", v.Path)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVHD()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readVHD()] read vhd %q\n", v.Path)
+    return vhd, nil
+}
+
+type readVHDArguments struct{
+    Path string
+}
+
+var readVHDScript = script.New("readVHD", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+if ( -not ( Test-Path -LiteralPath '{{.Path}}' ) ) {
+    throw "cannot find vhd '{{.Path}}'"
+}
+$VHDObject = Get-VHD -Path '{{.Path}}'
+
+$vhd = @{
+    Path                    = $VHDObject.Path
+    Format                  = $( [string]$VHDObject.VhdFormat ).ToLower()
+    Type                    = $( [string]$VHDObject.VhdType ).ToLower()
+    Size                    = $VHDObject.Size
+    BlockSize               = $VHDObject.BlockSize
+    LogicalSectorSize       = $VHDObject.LogicalSectorSize
+    PhysicalSectorSize      = $VHDObject.PhysicalSectorSize
+    ParentPath              = [string]$VHDObject.ParentPath
+
+    FileSize                = $VHDObject.FileSize
+    FragmentationPercentage = [int]$VHDObject.FragmentationPercentage
+    Attached                = $VHDObject.Attached
+}
+
+Write-Output $( ConvertTo-Json -InputObject $vhd )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateVHD(c *HypervClient, v *VHD, vhdProperties *VHD, allowShrink bool) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // convert vhdProperties to JSON
+    vhdPropertiesJSON, err := json.Marshal(vhdProperties)
+    if err != nil {
+        return err
+    }
+
+    // run script
+    err = runner.Run(c, updateVHDScript, updateVHDArguments{
+        Path:              v.Path,
+        VHDPropertiesJSON: string(vhdPropertiesJSON),
+        AllowShrink:       allowShrink,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVHD()] cannot update vhd %q\n", v.Path)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVHD()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVHD()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVHD()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/updateVHD()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/updateVHD()] updated vhd %q\n", v.Path)
+    return nil
+}
+
+type updateVHDArguments struct{
+    Path              string
+    VHDPropertiesJSON string
+    AllowShrink       bool
+}
+
+var updateVHDScript = script.New("updateVHD", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+if ( -not ( Test-Path -LiteralPath '{{.Path}}' ) ) {
+    throw "cannot find vhd '{{.Path}}'"
+}
+$VHDObject = Get-VHD -Path '{{.Path}}'
+
+$vhdProperties = $( ConvertFrom-Json -InputObject '{{.VHDPropertiesJSON}}' )
+
+$path = $VHDObject.Path
+if ( $vhdProperties.Path ) {
+    $path = $vhdProperties.Path
+}
+$type = [string]$VHDObject.VhdType
+if ( $vhdProperties.Type ) {
+    $type = $vhdProperties.Type
+}
+
+# the format of a vhd is derived from the extension of its path
+$extension = [System.IO.Path]::GetExtension($path)
+if ( $vhdProperties.Format -and ( $vhdProperties.Format -ne $extension.TrimStart('.').ToLower() ) ) {
+    throw "the extension of vhd '$path' doesn't match format '$( $vhdProperties.Format )'"
+}
+
+# convert the vhd to a temporary file, and replace the original file
+if ( ( $path -ne $VHDObject.Path ) -or ( $type -ne [string]$VHDObject.VhdType ) ) {
+    if ( $VHDObject.Attached ) {
+        throw "cannot convert vhd '{{.Path}}', it is attached to a running vm or mounted on the hyperv-server"
+    }
+    if ( ( $path -ne $VHDObject.Path ) -and ( Test-Path -LiteralPath $path ) ) {
+        throw "cannot convert vhd '{{.Path}}', vhd '$path' already exists"
+    }
+
+    $tempPath = Join-Path -Path $( Split-Path -Path $path -Parent ) -ChildPath "$( [System.IO.Path]::GetFileNameWithoutExtension($path) ).$( [guid]::NewGuid() )$extension"
+    try {
+        Convert-VHD -Path $VHDObject.Path -DestinationPath $tempPath -VHDType $type | Out-Default
+    }
+    catch {
+        Remove-Item -LiteralPath $tempPath -Force -ErrorAction 'Ignore'
+        throw
+    }
+    Remove-Item -LiteralPath $VHDObject.Path -Force | Out-Default
+    Move-Item -LiteralPath $tempPath -Destination $path | Out-Default
+
+    $VHDObject = Get-VHD -Path $path
+}
+
+# resize the vhd, an attached vhd can only be resized when it is a vhdx
+if ( $vhdProperties.Size -and ( $vhdProperties.Size -ne $VHDObject.Size ) ) {
+    if ( ( $vhdProperties.Size -lt $VHDObject.Size ) -and ( '{{.AllowShrink}}' -ne 'true' ) ) {
+        throw "cannot shrink vhd '$path' from $( $VHDObject.Size ) to $( $vhdProperties.Size ) bytes, shrinking is not allowed"
+    }
+    if ( $VHDObject.Attached -and ( [string]$VHDObject.VhdFormat -ne 'VHDX' ) ) {
+        throw "cannot resize vhd '$path', it is attached to a running vm or mounted on the hyperv-server, and only a vhdx can be resized while attached"
+    }
+
+    Resize-VHD -Path $VHDObject.Path -SizeBytes $vhdProperties.Size | Out-Default
+}
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteVHD(c *HypervClient, v *VHD) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err := runner.Run(c, deleteVHDScript, deleteVHDArguments{
+        Path: v.Path,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVHD()] cannot delete vhd %q\n", v.Path)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVHD()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVHD()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVHD()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/deleteVHD()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/deleteVHD()] deleted vhd %q\n", v.Path)
+    return nil
+}
+
+type deleteVHDArguments struct{
+    Path string
+}
+
+var deleteVHDScript = script.New("deleteVHD", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+if ( -not ( Test-Path -LiteralPath '{{.Path}}' ) ) {
+    throw "cannot find vhd '{{.Path}}'"
+}
+
+$VHDObject = Get-VHD -Path '{{.Path}}'
+if ( $VHDObject.Attached ) {
+    throw "cannot delete vhd '{{.Path}}', it is attached to a running vm or mounted on the hyperv-server"
+}
+
+Remove-Item -LiteralPath '{{.Path}}' -Force | Out-Default
+`)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,575 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type VM struct {
+    Name                           string   // required
+    Generation                     int      // 1 or 2 (default) - can only be set when creating the vm
+    Version                        string   // the configuration version, f.i. "9.0" - "" (default) is the default version of the hyperv-server, can only be upgraded after creating the vm
+    Path                           string   // the directory for the vm's files - "" (default) is the default path of the hyperv-server, can only be set when creating the vm
+    MemoryStartupBytes             int64    // required, a multiple of 2MB
+    ProcessorCount                 int      // 1 (default)
+    Notes                          string
+
+    // computed
+    Id                             string   // the GUID of the vm
+    State                          string   // "running", "off", "saved", "paused", ...
+    Heartbeat                      string   // the status of the guest's heartbeat integration service, f.i. "okapplicationshealthy", "nocontact", "disabled", ...
+}
+
+const (
+    VMStateRunning = "running"
+    VMStateOff     = "off"
+    VMStateSaved   = "saved"
+    VMStatePaused  = "paused"
+)
+
+// HeartbeatOK returns true when the guest's heartbeat integration service reports OK
+func (vm *VM) HeartbeatOK() bool {
+    return strings.HasPrefix(vm.Heartbeat, "ok")
+}
+
+//------------------------------------------------------------------------------
+
+func (c *HypervClient) CreateVM(vmProperties *VM) error {
+    if vmProperties.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVM(vmProperties)] missing 'vmProperties.Name'")
+    }
+    if vmProperties.MemoryStartupBytes == 0 {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVM(vmProperties)] missing 'vmProperties.MemoryStartupBytes'")
+    }
+
+    return createVM(c, vmProperties)
+}
+
+func (c *HypervClient) ReadVM(v *VM) (vm *VM, err error) {
+    if v.Name == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/ReadVM(v)] missing 'v.Name'")
+    }
+
+    return readVM(c, v)
+}
+
+func (c *HypervClient) UpdateVM(v *VM, vmProperties *VM) error {
+    if v.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/UpdateVM(v, vmProperties)] missing 'v.Name'")
+    }
+
+    return updateVM(c, v, vmProperties)
+}
+
+func (c *HypervClient) DeleteVM(v *VM) error {
+    if v.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/DeleteVM(v)] missing 'v.Name'")
+    }
+
+    return deleteVM(c, v)
+}
+
+func (c *HypervClient) ReadVMs() (vms []VM, err error) {
+    return readVMs(c)
+}
+
+// SetVMState changes the state of a vm to "running", "off", "saved" or "paused", without waiting for the vm to reach the state
+// the guest is shut down gracefully, the vm is turned off when the shutdown doesn't complete within shutdownTimeout seconds
+func (c *HypervClient) SetVMState(v *VM, state string, shutdownTimeout int) (turnedOff bool, err error) {
+    if v.Name == "" {
+        return false, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/SetVMState(v, state, shutdownTimeout)] missing 'v.Name'")
+    }
+    switch state {
+    case VMStateRunning, VMStateOff, VMStateSaved, VMStatePaused:
+    default:
+        return false, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/SetVMState(v, state, shutdownTimeout)] invalid 'state' %q", state)
+    }
+
+    return setVMState(c, v, state, shutdownTimeout)
+}
+
+//------------------------------------------------------------------------------
+
+func createVM(c *HypervClient, vmProperties *VM) error {
+    // convert vmProperties to JSON
+    vmPropertiesJSON, err := json.Marshal(vmProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVM()] cannot cannot convert 'vmProperties' to json for %q\n", vmProperties.Name)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, createVMScript, createVMArguments{
+        VMPropertiesJSON: escapeSingleQuotes(string(vmPropertiesJSON)),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVM()] cannot create vm %q\n", vmProperties.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVM()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVM()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVM()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/createVM()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/createVM()] created vm %q\n", vmProperties.Name)
+    return nil
+}
+
+type createVMArguments struct{
+    VMPropertiesJSON string
+}
+
+var createVMScript = script.New("createVM", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$vmProperties = $( ConvertFrom-Json -InputObject '{{.VMPropertiesJSON}}' )
+
+$VMObject = Get-VM -Name $vmProperties.Name -ErrorAction 'Ignore'
+if ( $VMObject ) {
+    throw "vm '$( $vmProperties.Name )' already exists"
+}
+
+$arguments = @{
+    Name               = $vmProperties.Name
+    MemoryStartupBytes = $vmProperties.MemoryStartupBytes
+    NoVHD              = $true
+}
+if ( $vmProperties.Generation ) {
+    $arguments.Generation = $vmProperties.Generation
+}
+if ( $vmProperties.Version ) {
+    $arguments.Version = $vmProperties.Version
+}
+if ( $vmProperties.Path ) {
+    $arguments.Path = $vmProperties.Path
+}
+
+$VMObject = New-VM @arguments
+
+$arguments = @{
+    VM    = $VMObject
+    Notes = $vmProperties.Notes
+}
+if ( $vmProperties.ProcessorCount ) {
+    $arguments.ProcessorCount = $vmProperties.ProcessorCount
+}
+
+Set-VM @arguments | Out-Default
+`)
+
+//------------------------------------------------------------------------------
+
+func readVM(c *HypervClient, v *VM) (vm *VM, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readVMScript, readVMArguments{
+        Name: v.Name,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVM()] cannot read vm %q\n", v.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVM()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVM()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVM()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readVM()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to vm
+    vm = new(VM)
+    err = json.Unmarshal(stdout.Bytes(), vm)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVM()] cannot convert json to 'vm' for %q\n", v.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVM()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readVM()] read vm %q\n", v.Name)
+    return vm, nil
+}
+
+type readVMArguments struct{
+    Name string
+}
+
+var readVMScript = script.New("readVM", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$VMObjects = @( Get-VM -Name '{{.Name}}' -ErrorAction 'Ignore' )
+if ( $VMObjects.Count -eq 0 ) {
+    throw "cannot find vm '{{.Name}}'"
+}
+if ( $VMObjects.Count -gt 1 ) {
+    throw "found $( $VMObjects.Count ) vms with name '{{.Name}}'"
+}
+$VMObject = $VMObjects[0]
+
+$VM = @{
+    Name               = $VMObject.Name
+    Generation         = $VMObject.Generation
+    Version            = [string]$VMObject.Version
+    Path               = $VMObject.Path
+    MemoryStartupBytes = $VMObject.MemoryStartup
+    ProcessorCount     = $VMObject.ProcessorCount
+    Notes              = $VMObject.Notes
+
+    Id                 = [string]$VMObject.Id
+    State              = $( [string]$VMObject.State ).ToLower()
+    Heartbeat          = $( [string]$VMObject.Heartbeat ).ToLower()
+}
+
+Write-Output $( ConvertTo-Json -InputObject $VM )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateVM(c *HypervClient, v *VM, vmProperties *VM) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // convert vmProperties to JSON
+    vmPropertiesJSON, err := json.Marshal(vmProperties)
+    if err != nil {
+        return err
+    }
+
+    // run script
+    err = runner.Run(c, updateVMScript, updateVMArguments{
+        Name:             v.Name,
+        VMPropertiesJSON: escapeSingleQuotes(string(vmPropertiesJSON)),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVM()] cannot update vm %q\n", v.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVM()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVM()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVM()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/updateVM()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/updateVM()] updated vm %q\n", v.Name)
+    return nil
+}
+
+type updateVMArguments struct{
+    Name             string
+    VMPropertiesJSON string
+}
+
+var updateVMScript = script.New("updateVM", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$VMObject = Get-VM -Name '{{.Name}}' -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '{{.Name}}'"
+}
+
+$vmProperties = $( ConvertFrom-Json -InputObject '{{.VMPropertiesJSON}}' )
+
+# the configuration version can only be upgraded, the vm must be off
+if ( $vmProperties.Version -and ( [version]$vmProperties.Version -gt [version]$VMObject.Version ) ) {
+    Update-VMVersion -VM $VMObject -Force | Out-Default
+    $VMObject = Get-VM -Id $VMObject.Id
+    if ( [version]$VMObject.Version -lt [version]$vmProperties.Version ) {
+        throw "cannot upgrade vm '{{.Name}}' to configuration version '$( $vmProperties.Version )', the hyperv-server upgraded to version '$( $VMObject.Version )'"
+    }
+}
+
+# memory and processors can only be changed when the vm is off
+$arguments = @{
+    VM    = $VMObject
+    Notes = $vmProperties.Notes
+}
+if ( $vmProperties.MemoryStartupBytes -and ( $vmProperties.MemoryStartupBytes -ne $VMObject.MemoryStartup ) ) {
+    $arguments.MemoryStartupBytes = $vmProperties.MemoryStartupBytes
+}
+if ( $vmProperties.ProcessorCount -and ( $vmProperties.ProcessorCount -ne $VMObject.ProcessorCount ) ) {
+    $arguments.ProcessorCount = $vmProperties.ProcessorCount
+}
+
+Set-VM @arguments | Out-Default
+
+if ( $vmProperties.Name -and ( $vmProperties.Name -ne $VMObject.Name ) ) {
+    Rename-VM -VM $VMObject -NewName $vmProperties.Name | Out-Default
+}
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteVM(c *HypervClient, v *VM) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err := runner.Run(c, deleteVMScript, deleteVMArguments{
+        Name: v.Name,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVM()] cannot delete vm %q\n", v.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVM()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVM()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVM()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/deleteVM()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/deleteVM()] deleted vm %q\n", v.Name)
+    return nil
+}
+
+type deleteVMArguments struct{
+    Name string
+}
+
+var deleteVMScript = script.New("deleteVM", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$VMObject = Get-VM -Name '{{.Name}}' -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '{{.Name}}'"
+}
+
+# a vm must be off before it can be removed
+if ( $VMObject.State -ne 'Off' ) {
+    Stop-VM -VM $VMObject -TurnOff -Force | Out-Default
+}
+
+# remark that the virtual hard disks of the vm are not removed
+Remove-VM -VM $VMObject -Force | Out-Default
+`)
+
+//------------------------------------------------------------------------------
+
+func readVMs(c *HypervClient) (vms []VM, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readVMsScript, nil, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMs()] cannot read vms\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMs()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMs()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMs()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readVMs()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to vms
+    err = json.Unmarshal(stdout.Bytes(), &vms)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMs()] cannot convert json to 'vms'\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMs()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readVMs()] read %d vms\n", len(vms))
+    return vms, nil
+}
+
+var readVMsScript = script.New("readVMs", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$VMs = @( Get-VM | ForEach-Object {
+    $VMObject = $_
+
+    @{
+        Name               = $VMObject.Name
+        Generation         = $VMObject.Generation
+        Version            = [string]$VMObject.Version
+        Path               = $VMObject.Path
+        MemoryStartupBytes = $VMObject.MemoryStartup
+        ProcessorCount     = $VMObject.ProcessorCount
+        Notes              = $VMObject.Notes
+
+        Id                 = [string]$VMObject.Id
+        State              = $( [string]$VMObject.State ).ToLower()
+        Heartbeat          = $( [string]$VMObject.Heartbeat ).ToLower()
+    }
+} )
+
+Write-Output $( ConvertTo-Json -InputObject $VMs )
+`)
+
+//------------------------------------------------------------------------------
+
+func setVMState(c *HypervClient, v *VM, state string, shutdownTimeout int) (turnedOff bool, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, setVMStateScript, setVMStateArguments{
+        Name:            v.Name,
+        State:           state,
+        ShutdownTimeout: shutdownTimeout,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/setVMState()] cannot set state %q for vm %q\n", state, v.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/setVMState()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/setVMState()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/setVMState()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/setVMState()] runner: %s", stderr.String())
+        }
+
+        return false, err
+    }
+
+    // convert stdout-JSON to result
+    var result struct{
+        TurnedOff bool
+    }
+    err = json.Unmarshal(stdout.Bytes(), &result)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/setVMState()] cannot convert json to 'result' for %q\n", v.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/setVMState()] json: %s", stdout.String())
+        return false, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/setVMState()] set state %q for vm %q\n", state, v.Name)
+    return result.TurnedOff, nil
+}
+
+type setVMStateArguments struct{
+    Name            string
+    State           string
+    ShutdownTimeout int
+}
+
+var setVMStateScript = script.New("setVMState", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$VMObject = Get-VM -Name '{{.Name}}' -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '{{.Name}}'"
+}
+
+$state = [string]$VMObject.State
+$turnedOff = $false
+
+function Stop-VMGracefully( $VMObject ) {
+    # shut down the guest, turn off the vm when the shutdown doesn't complete in time
+    $job = Stop-VM -VM $VMObject -Force -AsJob
+    $completed = Wait-Job -Job $job -Timeout {{.ShutdownTimeout}}
+    if ( $completed -and ( $job.State -eq 'Completed' ) ) {
+        Remove-Job -Job $job -Force
+        return $false
+    }
+    Stop-Job -Job $job
+    Remove-Job -Job $job -Force
+
+    Stop-VM -VM $( Get-VM -Id $VMObject.Id ) -TurnOff -Force | Out-Default
+    return $true
+}
+
+switch ( '{{.State}}' ) {
+    'running' {
+        if ( $state -eq 'Paused' ) {
+            Resume-VM -VM $VMObject | Out-Default
+        } elseif ( $state -ne 'Running' ) {
+            Start-VM -VM $VMObject | Out-Default
+        }
+    }
+    'off' {
+        if ( $state -eq 'Paused' ) {
+            Resume-VM -VM $VMObject | Out-Default
+            $state = 'Running'
+        } elseif ( $state -eq 'Saved' ) {
+            Start-VM -VM $VMObject | Out-Default   # restore the saved state, don't discard it
+            $state = 'Running'
+        }
+        if ( $state -ne 'Off' ) {
+            $turnedOff = Stop-VMGracefully $VMObject
+        }
+    }
+    'saved' {
+        if ( $state -eq 'Off' ) {
+            throw "cannot save vm '{{.Name}}' when it is off"
+        }
+        if ( $state -ne 'Saved' ) {
+            Save-VM -VM $VMObject | Out-Default
+        }
+    }
+    'paused' {
+        if ( ( $state -eq 'Off' ) -or ( $state -eq 'Saved' ) ) {
+            Start-VM -VM $VMObject | Out-Default
+            $state = 'Running'
+        }
+        if ( $state -ne 'Paused' ) {
+            Suspend-VM -VM $( Get-VM -Id $VMObject.Id ) | Out-Default
+        }
+    }
+}
+
+Write-Output $( ConvertTo-Json -InputObject @{ TurnedOff = $turnedOff } )
+`)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,376 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type VMDvdDrive struct {
+    VMName                         string   // required
+    ControllerType                 string   // required, "ide" for a generation 1 vm or "scsi" for a generation 2 vm - a dvd drive can only be added or removed when the vm is off
+    ControllerNumber               int      // ide: 0 or 1, scsi: 0 to 3
+    ControllerLocation             int      // ide: 0 or 1, scsi: 0 to 63
+    Path                           string   // the path of the iso on the hyperv-server - "" (default) is an ejected dvd drive
+}
+
+//------------------------------------------------------------------------------
+
+func (c *HypervClient) CreateVMDvdDrive(dvdProperties *VMDvdDrive) error {
+    if dvdProperties.VMName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVMDvdDrive(dvdProperties)] missing 'dvdProperties.VMName'")
+    }
+    if dvdProperties.ControllerType == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVMDvdDrive(dvdProperties)] missing 'dvdProperties.ControllerType'")
+    }
+
+    return createVMDvdDrive(c, dvdProperties)
+}
+
+func (c *HypervClient) ReadVMDvdDrive(dd *VMDvdDrive) (dvd *VMDvdDrive, err error) {
+    if dd.VMName == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/ReadVMDvdDrive(dd)] missing 'dd.VMName'")
+    }
+    if dd.ControllerType == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/ReadVMDvdDrive(dd)] missing 'dd.ControllerType'")
+    }
+
+    return readVMDvdDrive(c, dd)
+}
+
+func (c *HypervClient) UpdateVMDvdDrive(dd *VMDvdDrive, dvdProperties *VMDvdDrive) error {
+    if dd.VMName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/UpdateVMDvdDrive(dd, dvdProperties)] missing 'dd.VMName'")
+    }
+    if dd.ControllerType == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/UpdateVMDvdDrive(dd, dvdProperties)] missing 'dd.ControllerType'")
+    }
+
+    return updateVMDvdDrive(c, dd, dvdProperties)
+}
+
+func (c *HypervClient) DeleteVMDvdDrive(dd *VMDvdDrive) error {
+    if dd.VMName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/DeleteVMDvdDrive(dd)] missing 'dd.VMName'")
+    }
+    if dd.ControllerType == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/DeleteVMDvdDrive(dd)] missing 'dd.ControllerType'")
+    }
+
+    return deleteVMDvdDrive(c, dd)
+}
+
+//------------------------------------------------------------------------------
+
+func createVMDvdDrive(c *HypervClient, dvdProperties *VMDvdDrive) error {
+    // convert dvdProperties to JSON
+    dvdPropertiesJSON, err := json.Marshal(dvdProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMDvdDrive()] cannot cannot convert 'dvdProperties' to json for %q\n", dvdProperties.VMName)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, createVMDvdDriveScript, createVMDvdDriveArguments{
+        DVDPropertiesJSON: string(dvdPropertiesJSON),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMDvdDrive()] cannot create dvd drive for vm %q\n", dvdProperties.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMDvdDrive()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMDvdDrive()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMDvdDrive()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/createVMDvdDrive()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/createVMDvdDrive()] created dvd drive for vm %q\n", dvdProperties.VMName)
+    return nil
+}
+
+type createVMDvdDriveArguments struct{
+    DVDPropertiesJSON string
+}
+
+var createVMDvdDriveScript = script.New("createVMDvdDrive", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$dvdProperties = $( ConvertFrom-Json -InputObject '{{.DVDPropertiesJSON}}' )
+
+$VMObject = Get-VM -Name $dvdProperties.VMName -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '$( $dvdProperties.VMName )'"
+}
+
+$location = "$( $dvdProperties.ControllerType ) $( $dvdProperties.ControllerNumber ):$( $dvdProperties.ControllerLocation )"
+
+$VMDvdDriveObject = Get-VMDvdDrive -VM $VMObject -ControllerNumber $dvdProperties.ControllerNumber -ControllerLocation $dvdProperties.ControllerLocation -ErrorAction 'Ignore'
+if ( $VMDvdDriveObject ) {
+    throw "dvd drive '$( [string]$VMDvdDriveObject.ControllerType ) $( $dvdProperties.ControllerNumber ):$( $dvdProperties.ControllerLocation )' already exists for vm '$( $dvdProperties.VMName )'"
+}
+
+# a generation 1 vm supports dvd drives on ide controllers only, a generation 2 vm on scsi controllers only
+$controllerType = 'scsi'
+if ( $VMObject.Generation -eq 1 ) {
+    $controllerType = 'ide'
+}
+if ( $dvdProperties.ControllerType -ne $controllerType ) {
+    throw "cannot add dvd drive '$location' to vm '$( $dvdProperties.VMName )', a generation $( $VMObject.Generation ) vm only supports dvd drives on $controllerType controllers"
+}
+
+# dvd drives can only be added when the vm is off
+if ( $VMObject.State -ne 'Off' ) {
+    throw "cannot add dvd drive '$location' to vm '$( $dvdProperties.VMName )', the vm must be off"
+}
+
+$arguments = @{
+    VM                 = $VMObject
+    ControllerNumber   = $dvdProperties.ControllerNumber
+    ControllerLocation = $dvdProperties.ControllerLocation
+}
+if ( $dvdProperties.Path ) {
+    $arguments.Path = $dvdProperties.Path
+}
+
+Add-VMDvdDrive @arguments | Out-Default
+`)
+
+//------------------------------------------------------------------------------
+
+func readVMDvdDrive(c *HypervClient, dd *VMDvdDrive) (dvd *VMDvdDrive, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readVMDvdDriveScript, readVMDvdDriveArguments{
+        VMName:             dd.VMName,
+        ControllerType:     dd.ControllerType,
+        ControllerNumber:   dd.ControllerNumber,
+        ControllerLocation: dd.ControllerLocation,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMDvdDrive()] cannot read dvd drive %s %d:%d for vm %q\n", dd.ControllerType, dd.ControllerNumber, dd.ControllerLocation, dd.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMDvdDrive()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMDvdDrive()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMDvdDrive()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readVMDvdDrive()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to dvd
+    dvd = new(VMDvdDrive)
+    err = json.Unmarshal(stdout.Bytes(), dvd)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMDvdDrive()] cannot convert json to 'dvd' for %q\n", dd.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMDvdDrive()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readVMDvdDrive()] read dvd drive %s %d:%d for vm %q\n", dd.ControllerType, dd.ControllerNumber, dd.ControllerLocation, dd.VMName)
+    return dvd, nil
+}
+
+type readVMDvdDriveArguments struct{
+    VMName             string
+    ControllerType     string
+    ControllerNumber   int
+    ControllerLocation int
+}
+
+var readVMDvdDriveScript = script.New("readVMDvdDrive", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$VMObject = Get-VM -Name '{{.VMName}}' -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '{{.VMName}}'"
+}
+
+$VMDvdDriveObject = Get-VMDvdDrive -VM $VMObject -ControllerNumber {{.ControllerNumber}} -ControllerLocation {{.ControllerLocation}} -ErrorAction 'Ignore' | Where-Object { [string]$_.ControllerType -eq '{{.ControllerType}}' }
+if ( -not $VMDvdDriveObject ) {
+    throw "cannot find dvd drive '{{.ControllerType}} {{.ControllerNumber}}:{{.ControllerLocation}}' for vm '{{.VMName}}'"
+}
+
+$dvd = @{
+    VMName             = $VMDvdDriveObject.VMName
+    ControllerType     = $( [string]$VMDvdDriveObject.ControllerType ).ToLower()
+    ControllerNumber   = $VMDvdDriveObject.ControllerNumber
+    ControllerLocation = $VMDvdDriveObject.ControllerLocation
+    Path               = [string]$VMDvdDriveObject.Path
+}
+
+Write-Output $( ConvertTo-Json -InputObject $dvd )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateVMDvdDrive(c *HypervClient, dd *VMDvdDrive, dvdProperties *VMDvdDrive) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // convert dvdProperties to JSON
+    dvdPropertiesJSON, err := json.Marshal(dvdProperties)
+    if err != nil {
+        return err
+    }
+
+    // run script
+    err = runner.Run(c, updateVMDvdDriveScript, updateVMDvdDriveArguments{
+        VMName:             dd.VMName,
+        ControllerType:     dd.ControllerType,
+        ControllerNumber:   dd.ControllerNumber,
+        ControllerLocation: dd.ControllerLocation,
+        DVDPropertiesJSON:  string(dvdPropertiesJSON),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVMDvdDrive()] cannot update dvd drive %s %d:%d for vm %q\n", dd.ControllerType, dd.ControllerNumber, dd.ControllerLocation, dd.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVMDvdDrive()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVMDvdDrive()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVMDvdDrive()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/updateVMDvdDrive()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/updateVMDvdDrive()] updated dvd drive %s %d:%d for vm %q\n", dd.ControllerType, dd.ControllerNumber, dd.ControllerLocation, dd.VMName)
+    return nil
+}
+
+type updateVMDvdDriveArguments struct{
+    VMName             string
+    ControllerType     string
+    ControllerNumber   int
+    ControllerLocation int
+    DVDPropertiesJSON  string
+}
+
+var updateVMDvdDriveScript = script.New("updateVMDvdDrive", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$VMObject = Get-VM -Name '{{.VMName}}' -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '{{.VMName}}'"
+}
+
+$VMDvdDriveObject = Get-VMDvdDrive -VM $VMObject -ControllerNumber {{.ControllerNumber}} -ControllerLocation {{.ControllerLocation}} -ErrorAction 'Ignore' | Where-Object { [string]$_.ControllerType -eq '{{.ControllerType}}' }
+if ( -not $VMDvdDriveObject ) {
+    throw "cannot find dvd drive '{{.ControllerType}} {{.ControllerNumber}}:{{.ControllerLocation}}' for vm '{{.VMName}}'"
+}
+
+$dvdProperties = $( ConvertFrom-Json -InputObject '{{.DVDPropertiesJSON}}' )
+
+# the iso is changed in place, also when the vm is running - an empty path ejects the iso
+if ( $dvdProperties.Path -ne [string]$VMDvdDriveObject.Path ) {
+    $path = $null
+    if ( $dvdProperties.Path ) {
+        $path = $dvdProperties.Path
+    }
+    Set-VMDvdDrive -VMDvdDrive $VMDvdDriveObject -Path $path | Out-Default
+}
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteVMDvdDrive(c *HypervClient, dd *VMDvdDrive) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err := runner.Run(c, deleteVMDvdDriveScript, deleteVMDvdDriveArguments{
+        VMName:             dd.VMName,
+        ControllerType:     dd.ControllerType,
+        ControllerNumber:   dd.ControllerNumber,
+        ControllerLocation: dd.ControllerLocation,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVMDvdDrive()] cannot delete dvd drive %s %d:%d for vm %q\n", dd.ControllerType, dd.ControllerNumber, dd.ControllerLocation, dd.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVMDvdDrive()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVMDvdDrive()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVMDvdDrive()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/deleteVMDvdDrive()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/deleteVMDvdDrive()] deleted dvd drive %s %d:%d for vm %q\n", dd.ControllerType, dd.ControllerNumber, dd.ControllerLocation, dd.VMName)
+    return nil
+}
+
+type deleteVMDvdDriveArguments struct{
+    VMName             string
+    ControllerType     string
+    ControllerNumber   int
+    ControllerLocation int
+}
+
+var deleteVMDvdDriveScript = script.New("deleteVMDvdDrive", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$VMObject = Get-VM -Name '{{.VMName}}' -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '{{.VMName}}'"
+}
+
+$VMDvdDriveObject = Get-VMDvdDrive -VM $VMObject -ControllerNumber {{.ControllerNumber}} -ControllerLocation {{.ControllerLocation}} -ErrorAction 'Ignore' | Where-Object { [string]$_.ControllerType -eq '{{.ControllerType}}' }
+if ( -not $VMDvdDriveObject ) {
+    throw "cannot find dvd drive '{{.ControllerType}} {{.ControllerNumber}}:{{.ControllerLocation}}' for vm '{{.VMName}}'"
+}
+
+# dvd drives can only be removed when the vm is off
+if ( $VMObject.State -ne 'Off' ) {
+    throw "cannot remove dvd drive '{{.ControllerType}} {{.ControllerNumber}}:{{.ControllerLocation}}' from vm '{{.VMName}}', the vm must be off"
+}
+
+# remark that the iso is not removed
+Remove-VMDvdDrive -VMDvdDrive $VMDvdDriveObject | Out-Default
+`)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,386 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type VMHardDiskDrive struct {
+    VMName                         string   // required
+    ControllerType                 string   // required, "ide" or "scsi" - a hard disk drive can only be added to or removed from an ide controller when the vm is off
+    ControllerNumber               int      // ide: 0 or 1, scsi: 0 to 3
+    ControllerLocation             int      // ide: 0 or 1, scsi: 0 to 63
+    Path                           string   // required, the path of the vhd on the hyperv-server
+}
+
+//------------------------------------------------------------------------------
+
+func (c *HypervClient) CreateVMHardDiskDrive(hddProperties *VMHardDiskDrive) error {
+    if hddProperties.VMName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVMHardDiskDrive(hddProperties)] missing 'hddProperties.VMName'")
+    }
+    if hddProperties.ControllerType == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVMHardDiskDrive(hddProperties)] missing 'hddProperties.ControllerType'")
+    }
+    if hddProperties.Path == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVMHardDiskDrive(hddProperties)] missing 'hddProperties.Path'")
+    }
+
+    return createVMHardDiskDrive(c, hddProperties)
+}
+
+func (c *HypervClient) ReadVMHardDiskDrive(h *VMHardDiskDrive) (hdd *VMHardDiskDrive, err error) {
+    if h.VMName == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/ReadVMHardDiskDrive(h)] missing 'h.VMName'")
+    }
+    if h.ControllerType == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/ReadVMHardDiskDrive(h)] missing 'h.ControllerType'")
+    }
+
+    return readVMHardDiskDrive(c, h)
+}
+
+func (c *HypervClient) UpdateVMHardDiskDrive(h *VMHardDiskDrive, hddProperties *VMHardDiskDrive) error {
+    if h.VMName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/UpdateVMHardDiskDrive(h, hddProperties)] missing 'h.VMName'")
+    }
+    if h.ControllerType == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/UpdateVMHardDiskDrive(h, hddProperties)] missing 'h.ControllerType'")
+    }
+    if hddProperties.Path == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/UpdateVMHardDiskDrive(h, hddProperties)] missing 'hddProperties.Path'")
+    }
+
+    return updateVMHardDiskDrive(c, h, hddProperties)
+}
+
+func (c *HypervClient) DeleteVMHardDiskDrive(h *VMHardDiskDrive) error {
+    if h.VMName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/DeleteVMHardDiskDrive(h)] missing 'h.VMName'")
+    }
+    if h.ControllerType == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/DeleteVMHardDiskDrive(h)] missing 'h.ControllerType'")
+    }
+
+    return deleteVMHardDiskDrive(c, h)
+}
+
+//------------------------------------------------------------------------------
+
+func createVMHardDiskDrive(c *HypervClient, hddProperties *VMHardDiskDrive) error {
+    // convert hddProperties to JSON
+    hddPropertiesJSON, err := json.Marshal(hddProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMHardDiskDrive()] cannot cannot convert 'hddProperties' to json for %q\n", hddProperties.VMName)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, createVMHardDiskDriveScript, createVMHardDiskDriveArguments{
+        HDDPropertiesJSON: string(hddPropertiesJSON),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMHardDiskDrive()] cannot create hard disk drive for vm %q\n", hddProperties.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMHardDiskDrive()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMHardDiskDrive()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMHardDiskDrive()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/createVMHardDiskDrive()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/createVMHardDiskDrive()] created hard disk drive for vm %q\n", hddProperties.VMName)
+    return nil
+}
+
+type createVMHardDiskDriveArguments struct{
+    HDDPropertiesJSON string
+}
+
+var createVMHardDiskDriveScript = script.New("createVMHardDiskDrive", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$hddProperties = $( ConvertFrom-Json -InputObject '{{.HDDPropertiesJSON}}' )
+
+$VMObject = Get-VM -Name $hddProperties.VMName -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '$( $hddProperties.VMName )'"
+}
+
+$location = "$( $hddProperties.ControllerType ) $( $hddProperties.ControllerNumber ):$( $hddProperties.ControllerLocation )"
+
+$VMHardDiskDriveObject = Get-VMHardDiskDrive -VM $VMObject -ControllerType $hddProperties.ControllerType -ControllerNumber $hddProperties.ControllerNumber -ControllerLocation $hddProperties.ControllerLocation -ErrorAction 'Ignore'
+if ( $VMHardDiskDriveObject ) {
+    throw "hard disk drive '$location' already exists for vm '$( $hddProperties.VMName )'"
+}
+
+# only scsi supports adding hard disk drives when the vm is running
+if ( $hddProperties.ControllerType -eq 'ide' ) {
+    if ( $VMObject.State -ne 'Off' ) {
+        throw "cannot add hard disk drive '$location' to vm '$( $hddProperties.VMName )', the vm must be off"
+    }
+}
+else {
+    # a generation 1 vm has no scsi controllers by default, scsi controllers can only be added when the vm is off
+    $controllers = @( Get-VMScsiController -VM $VMObject )
+    if ( $controllers.Count -le $hddProperties.ControllerNumber ) {
+        if ( $VMObject.State -ne 'Off' ) {
+            throw "cannot add hard disk drive '$location' to vm '$( $hddProperties.VMName )', scsi controller $( $hddProperties.ControllerNumber ) doesn't exist and can only be added when the vm is off"
+        }
+        for ( $i = $controllers.Count; $i -le $hddProperties.ControllerNumber; $i++ ) {
+            Add-VMScsiController -VM $VMObject | Out-Default
+        }
+    }
+}
+
+$arguments = @{
+    VM                 = $VMObject
+    ControllerType     = $hddProperties.ControllerType
+    ControllerNumber   = $hddProperties.ControllerNumber
+    ControllerLocation = $hddProperties.ControllerLocation
+    Path               = $hddProperties.Path
+}
+
+Add-VMHardDiskDrive @arguments | Out-Default
+`)
+
+//------------------------------------------------------------------------------
+
+func readVMHardDiskDrive(c *HypervClient, h *VMHardDiskDrive) (hdd *VMHardDiskDrive, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readVMHardDiskDriveScript, readVMHardDiskDriveArguments{
+        VMName:             h.VMName,
+        ControllerType:     h.ControllerType,
+        ControllerNumber:   h.ControllerNumber,
+        ControllerLocation: h.ControllerLocation,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMHardDiskDrive()] cannot read hard disk drive %s %d:%d for vm %q\n", h.ControllerType, h.ControllerNumber, h.ControllerLocation, h.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMHardDiskDrive()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMHardDiskDrive()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMHardDiskDrive()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readVMHardDiskDrive()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to hdd
+    hdd = new(VMHardDiskDrive)
+    err = json.Unmarshal(stdout.Bytes(), hdd)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMHardDiskDrive()] cannot convert json to 'hdd' for %q\n", h.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMHardDiskDrive()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readVMHardDiskDrive()] read hard disk drive %s %d:%d for vm %q\n", h.ControllerType, h.ControllerNumber, h.ControllerLocation, h.VMName)
+    return hdd, nil
+}
+
+type readVMHardDiskDriveArguments struct{
+    VMName             string
+    ControllerType     string
+    ControllerNumber   int
+    ControllerLocation int
+}
+
+var readVMHardDiskDriveScript = script.New("readVMHardDiskDrive", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$VMObject = Get-VM -Name '{{.VMName}}' -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '{{.VMName}}'"
+}
+
+$VMHardDiskDriveObject = Get-VMHardDiskDrive -VM $VMObject -ControllerType '{{.ControllerType}}' -ControllerNumber {{.ControllerNumber}} -ControllerLocation {{.ControllerLocation}} -ErrorAction 'Ignore'
+if ( -not $VMHardDiskDriveObject ) {
+    throw "cannot find hard disk drive '{{.ControllerType}} {{.ControllerNumber}}:{{.ControllerLocation}}' for vm '{{.VMName}}'"
+}
+
+$hdd = @{
+    VMName             = $VMHardDiskDriveObject.VMName
+    ControllerType     = $( [string]$VMHardDiskDriveObject.ControllerType ).ToLower()
+    ControllerNumber   = $VMHardDiskDriveObject.ControllerNumber
+    ControllerLocation = $VMHardDiskDriveObject.ControllerLocation
+    Path               = [string]$VMHardDiskDriveObject.Path
+}
+
+Write-Output $( ConvertTo-Json -InputObject $hdd )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateVMHardDiskDrive(c *HypervClient, h *VMHardDiskDrive, hddProperties *VMHardDiskDrive) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // convert hddProperties to JSON
+    hddPropertiesJSON, err := json.Marshal(hddProperties)
+    if err != nil {
+        return err
+    }
+
+    // run script
+    err = runner.Run(c, updateVMHardDiskDriveScript, updateVMHardDiskDriveArguments{
+        VMName:             h.VMName,
+        ControllerType:     h.ControllerType,
+        ControllerNumber:   h.ControllerNumber,
+        ControllerLocation: h.ControllerLocation,
+        HDDPropertiesJSON:  string(hddPropertiesJSON),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVMHardDiskDrive()] cannot update hard disk drive %s %d:%d for vm %q\n", h.ControllerType, h.ControllerNumber, h.ControllerLocation, h.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVMHardDiskDrive()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVMHardDiskDrive()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVMHardDiskDrive()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/updateVMHardDiskDrive()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/updateVMHardDiskDrive()] updated hard disk drive %s %d:%d for vm %q\n", h.ControllerType, h.ControllerNumber, h.ControllerLocation, h.VMName)
+    return nil
+}
+
+type updateVMHardDiskDriveArguments struct{
+    VMName             string
+    ControllerType     string
+    ControllerNumber   int
+    ControllerLocation int
+    HDDPropertiesJSON  string
+}
+
+var updateVMHardDiskDriveScript = script.New("updateVMHardDiskDrive", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$VMObject = Get-VM -Name '{{.VMName}}' -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '{{.VMName}}'"
+}
+
+$VMHardDiskDriveObject = Get-VMHardDiskDrive -VM $VMObject -ControllerType '{{.ControllerType}}' -ControllerNumber {{.ControllerNumber}} -ControllerLocation {{.ControllerLocation}} -ErrorAction 'Ignore'
+if ( -not $VMHardDiskDriveObject ) {
+    throw "cannot find hard disk drive '{{.ControllerType}} {{.ControllerNumber}}:{{.ControllerLocation}}' for vm '{{.VMName}}'"
+}
+
+$hddProperties = $( ConvertFrom-Json -InputObject '{{.HDDPropertiesJSON}}' )
+
+# only scsi supports changing hard disk drives when the vm is running
+if ( ( '{{.ControllerType}}' -eq 'ide' ) -and ( $VMObject.State -ne 'Off' ) ) {
+    throw "cannot change hard disk drive '{{.ControllerType}} {{.ControllerNumber}}:{{.ControllerLocation}}' for vm '{{.VMName}}', the vm must be off"
+}
+
+if ( $hddProperties.Path -ne $VMHardDiskDriveObject.Path ) {
+    Set-VMHardDiskDrive -VMHardDiskDrive $VMHardDiskDriveObject -Path $hddProperties.Path | Out-Default
+}
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteVMHardDiskDrive(c *HypervClient, h *VMHardDiskDrive) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err := runner.Run(c, deleteVMHardDiskDriveScript, deleteVMHardDiskDriveArguments{
+        VMName:             h.VMName,
+        ControllerType:     h.ControllerType,
+        ControllerNumber:   h.ControllerNumber,
+        ControllerLocation: h.ControllerLocation,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVMHardDiskDrive()] cannot delete hard disk drive %s %d:%d for vm %q\n", h.ControllerType, h.ControllerNumber, h.ControllerLocation, h.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVMHardDiskDrive()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVMHardDiskDrive()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVMHardDiskDrive()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/deleteVMHardDiskDrive()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/deleteVMHardDiskDrive()] deleted hard disk drive %s %d:%d for vm %q\n", h.ControllerType, h.ControllerNumber, h.ControllerLocation, h.VMName)
+    return nil
+}
+
+type deleteVMHardDiskDriveArguments struct{
+    VMName             string
+    ControllerType     string
+    ControllerNumber   int
+    ControllerLocation int
+}
+
+var deleteVMHardDiskDriveScript = script.New("deleteVMHardDiskDrive", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$VMObject = Get-VM -Name '{{.VMName}}' -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '{{.VMName}}'"
+}
+
+$VMHardDiskDriveObject = Get-VMHardDiskDrive -VM $VMObject -ControllerType '{{.ControllerType}}' -ControllerNumber {{.ControllerNumber}} -ControllerLocation {{.ControllerLocation}} -ErrorAction 'Ignore'
+if ( -not $VMHardDiskDriveObject ) {
+    throw "cannot find hard disk drive '{{.ControllerType}} {{.ControllerNumber}}:{{.ControllerLocation}}' for vm '{{.VMName}}'"
+}
+
+# only scsi supports removing hard disk drives when the vm is running
+if ( ( '{{.ControllerType}}' -eq 'ide' ) -and ( $VMObject.State -ne 'Off' ) ) {
+    throw "cannot remove hard disk drive '{{.ControllerType}} {{.ControllerNumber}}:{{.ControllerLocation}}' from vm '{{.VMName}}', the vm must be off"
+}
+
+# remark that the vhd is not removed
+Remove-VMHardDiskDrive -VMHardDiskDrive $VMHardDiskDriveObject | Out-Default
+`)
+
+//------------------------------------------------------------------------------
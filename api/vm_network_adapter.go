@@ -0,0 +1,420 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type VMNetworkAdapter struct {
+    VMName                         string   // required
+    Name                           string   // required, must be unique for the vm
+    SwitchName                     string   // "" (default) is not connected
+    MacAddress                     string   // "" (default) is left untouched, a new adapter gets a dynamic mac-address
+    DynamicMacAddress              bool     // true changes a static mac-address to a dynamic mac-address
+    VlanId                         int      // 0 (default) is untagged
+    MacAddressSpoofing             bool
+    DhcpGuard                      bool
+    RouterGuard                    bool
+    DeviceNaming                   bool     // the name of the adapter is exposed to the guest, requires a generation 2 vm
+
+    // computed
+    IPAddresses                    []string // reported by the guest's integration services
+}
+
+//------------------------------------------------------------------------------
+
+func (c *HypervClient) CreateVMNetworkAdapter(vmnaProperties *VMNetworkAdapter) error {
+    if vmnaProperties.VMName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVMNetworkAdapter(vmnaProperties)] missing 'vmnaProperties.VMName'")
+    }
+    if vmnaProperties.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVMNetworkAdapter(vmnaProperties)] missing 'vmnaProperties.Name'")
+    }
+
+    return createVMNetworkAdapter(c, vmnaProperties)
+}
+
+func (c *HypervClient) ReadVMNetworkAdapter(vmna *VMNetworkAdapter) (vmNetworkAdapter *VMNetworkAdapter, err error) {
+    if vmna.VMName == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/ReadVMNetworkAdapter(vmna)] missing 'vmna.VMName'")
+    }
+    if vmna.Name == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/ReadVMNetworkAdapter(vmna)] missing 'vmna.Name'")
+    }
+
+    return readVMNetworkAdapter(c, vmna)
+}
+
+func (c *HypervClient) UpdateVMNetworkAdapter(vmna *VMNetworkAdapter, vmnaProperties *VMNetworkAdapter) error {
+    if vmna.VMName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/UpdateVMNetworkAdapter(vmna, vmnaProperties)] missing 'vmna.VMName'")
+    }
+    if vmna.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/UpdateVMNetworkAdapter(vmna, vmnaProperties)] missing 'vmna.Name'")
+    }
+
+    return updateVMNetworkAdapter(c, vmna, vmnaProperties)
+}
+
+func (c *HypervClient) DeleteVMNetworkAdapter(vmna *VMNetworkAdapter) error {
+    if vmna.VMName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/DeleteVMNetworkAdapter(vmna)] missing 'vmna.VMName'")
+    }
+    if vmna.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/DeleteVMNetworkAdapter(vmna)] missing 'vmna.Name'")
+    }
+
+    return deleteVMNetworkAdapter(c, vmna)
+}
+
+//------------------------------------------------------------------------------
+
+func createVMNetworkAdapter(c *HypervClient, vmnaProperties *VMNetworkAdapter) error {
+    // convert vmnaProperties to JSON
+    vmnaPropertiesJSON, err := json.Marshal(vmnaProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMNetworkAdapter()] cannot cannot convert 'vmnaProperties' to json for %q\n", vmnaProperties.Name)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, createVMNetworkAdapterScript, createVMNetworkAdapterArguments{
+        VMNAPropertiesJSON: string(vmnaPropertiesJSON),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMNetworkAdapter()] cannot create network adapter %q for vm %q\n", vmnaProperties.Name, vmnaProperties.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMNetworkAdapter()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMNetworkAdapter()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/createVMNetworkAdapter()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/createVMNetworkAdapter()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/createVMNetworkAdapter()] created network adapter %q for vm %q\n", vmnaProperties.Name, vmnaProperties.VMName)
+    return nil
+}
+
+type createVMNetworkAdapterArguments struct{
+    VMNAPropertiesJSON string
+}
+
+var createVMNetworkAdapterScript = script.New("createVMNetworkAdapter", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$vmnaProperties = $( ConvertFrom-Json -InputObject '{{.VMNAPropertiesJSON}}' )
+
+$VMObject = Get-VM -Name $vmnaProperties.VMName -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '$( $vmnaProperties.VMName )'"
+}
+
+$VMNetworkAdapterObject = Get-VMNetworkAdapter -VM $VMObject -Name $vmnaProperties.Name -ErrorAction 'Ignore'
+if ( $VMNetworkAdapterObject ) {
+    throw "network adapter '$( $vmnaProperties.Name )' already exists for vm '$( $vmnaProperties.VMName )'"
+}
+
+$arguments = @{
+    VM       = $VMObject
+    Name     = $vmnaProperties.Name
+    Passthru = $true
+}
+
+if ( $vmnaProperties.SwitchName ) {
+    $VMSwitchObject = Get-VMSwitch -Name $vmnaProperties.SwitchName -ErrorAction 'Ignore'
+    if ( -not $VMSwitchObject ) {
+        throw "cannot find vswitch '$( $vmnaProperties.SwitchName )'"
+    }
+
+    $arguments.SwitchName = $vmnaProperties.SwitchName
+}
+
+if ( $vmnaProperties.MacAddress ) {
+    $arguments.StaticMacAddress = $vmnaProperties.MacAddress -replace '[-:\.]', ''
+}
+
+$VMNetworkAdapterObject = Add-VMNetworkAdapter @arguments
+
+if ( $vmnaProperties.VlanId -gt 0 ) {
+    Set-VMNetworkAdapterVlan -VMNetworkAdapter $VMNetworkAdapterObject -Access -VlanId $vmnaProperties.VlanId | Out-Default
+}
+
+$arguments = @{
+    VMNetworkAdapter   = $VMNetworkAdapterObject
+    MacAddressSpoofing = $( if ( $vmnaProperties.MacAddressSpoofing ) { 'On' } else { 'Off' } )
+    DhcpGuard          = $( if ( $vmnaProperties.DhcpGuard ) { 'On' } else { 'Off' } )
+    RouterGuard        = $( if ( $vmnaProperties.RouterGuard ) { 'On' } else { 'Off' } )
+}
+
+if ( $vmnaProperties.DeviceNaming ) {   # only when enabled, device naming isn't supported by a generation 1 vm
+    $arguments.DeviceNaming = 'On'
+}
+
+Set-VMNetworkAdapter @arguments | Out-Default
+`)
+
+//------------------------------------------------------------------------------
+
+func readVMNetworkAdapter(c *HypervClient, vmna *VMNetworkAdapter) (vmNetworkAdapter *VMNetworkAdapter, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readVMNetworkAdapterScript, readVMNetworkAdapterArguments{
+        VMName: vmna.VMName,
+        Name:   vmna.Name,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMNetworkAdapter()] cannot read network adapter %q for vm %q\n", vmna.Name, vmna.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMNetworkAdapter()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMNetworkAdapter()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMNetworkAdapter()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readVMNetworkAdapter()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to vmNetworkAdapter
+    vmNetworkAdapter = new(VMNetworkAdapter)
+    err = json.Unmarshal(stdout.Bytes(), vmNetworkAdapter)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMNetworkAdapter()] cannot convert json to 'vmNetworkAdapter' for %q\n", vmna.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMNetworkAdapter()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readVMNetworkAdapter()] read network adapter %q for vm %q\n", vmna.Name, vmna.VMName)
+    return vmNetworkAdapter, nil
+}
+
+type readVMNetworkAdapterArguments struct{
+    VMName string
+    Name   string
+}
+
+var readVMNetworkAdapterScript = script.New("readVMNetworkAdapter", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$VMObject = Get-VM -Name '{{.VMName}}' -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '{{.VMName}}'"
+}
+
+$VMNetworkAdapterObjects = @( Get-VMNetworkAdapter -VM $VMObject -Name '{{.Name}}' -ErrorAction 'Ignore' )
+if ( $VMNetworkAdapterObjects.Count -eq 0 ) {
+    throw "cannot find network adapter '{{.Name}}' for vm '{{.VMName}}'"
+}
+if ( $VMNetworkAdapterObjects.Count -gt 1 ) {
+    throw "found $( $VMNetworkAdapterObjects.Count ) network adapters with name '{{.Name}}' for vm '{{.VMName}}'"
+}
+$VMNetworkAdapterObject = $VMNetworkAdapterObjects[0]
+
+$VMNetworkAdapterVlanObject = Get-VMNetworkAdapterVlan -VMNetworkAdapter $VMNetworkAdapterObject
+
+$VMNetworkAdapter = @{
+    VMName             = $VMNetworkAdapterObject.VMName
+    Name               = $VMNetworkAdapterObject.Name
+    SwitchName         = [string]$VMNetworkAdapterObject.SwitchName
+    MacAddress         = $VMNetworkAdapterObject.MacAddress
+    DynamicMacAddress  = $VMNetworkAdapterObject.DynamicMacAddressEnabled
+    VlanId             = [int]$VMNetworkAdapterVlanObject.AccessVlanId
+    MacAddressSpoofing = ( [string]$VMNetworkAdapterObject.MacAddressSpoofing -eq 'On' )
+    DhcpGuard          = ( [string]$VMNetworkAdapterObject.DhcpGuard -eq 'On' )
+    RouterGuard        = ( [string]$VMNetworkAdapterObject.RouterGuard -eq 'On' )
+    DeviceNaming       = ( [string]$VMNetworkAdapterObject.DeviceNaming -eq 'On' )
+
+    IPAddresses        = @( $VMNetworkAdapterObject.IPAddresses | ForEach-Object { [string]$_ } )
+}
+
+Write-Output $( ConvertTo-Json -InputObject $VMNetworkAdapter )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateVMNetworkAdapter(c *HypervClient, vmna *VMNetworkAdapter, vmnaProperties *VMNetworkAdapter) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // convert vmnaProperties to JSON
+    vmnaPropertiesJSON, err := json.Marshal(vmnaProperties)
+    if err != nil {
+        return err
+    }
+
+    // run script
+    err = runner.Run(c, updateVMNetworkAdapterScript, updateVMNetworkAdapterArguments{
+        VMName:             vmna.VMName,
+        Name:               vmna.Name,
+        VMNAPropertiesJSON: string(vmnaPropertiesJSON),
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVMNetworkAdapter()] cannot update network adapter %q for vm %q\n", vmna.Name, vmna.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVMNetworkAdapter()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVMNetworkAdapter()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVMNetworkAdapter()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/updateVMNetworkAdapter()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/updateVMNetworkAdapter()] updated network adapter %q for vm %q\n", vmna.Name, vmna.VMName)
+    return nil
+}
+
+type updateVMNetworkAdapterArguments struct{
+    VMName             string
+    Name               string
+    VMNAPropertiesJSON string
+}
+
+var updateVMNetworkAdapterScript = script.New("updateVMNetworkAdapter", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$VMObject = Get-VM -Name '{{.VMName}}' -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '{{.VMName}}'"
+}
+
+$VMNetworkAdapterObject = Get-VMNetworkAdapter -VM $VMObject -Name '{{.Name}}' -ErrorAction 'Ignore'
+if ( -not $VMNetworkAdapterObject ) {
+    throw "cannot find network adapter '{{.Name}}' for vm '{{.VMName}}'"
+}
+
+$vmnaProperties = $( ConvertFrom-Json -InputObject '{{.VMNAPropertiesJSON}}' )
+
+# the adapter is re-connected in place, also when the vm is running
+if ( $vmnaProperties.SwitchName -ne [string]$VMNetworkAdapterObject.SwitchName ) {
+    if ( $vmnaProperties.SwitchName ) {
+        Connect-VMNetworkAdapter -VMNetworkAdapter $VMNetworkAdapterObject -SwitchName $vmnaProperties.SwitchName | Out-Default
+    } else {
+        Disconnect-VMNetworkAdapter -VMNetworkAdapter $VMNetworkAdapterObject | Out-Default
+    }
+}
+
+if ( $vmnaProperties.VlanId -gt 0 ) {
+    Set-VMNetworkAdapterVlan -VMNetworkAdapter $VMNetworkAdapterObject -Access -VlanId $vmnaProperties.VlanId | Out-Default
+} else {
+    Set-VMNetworkAdapterVlan -VMNetworkAdapter $VMNetworkAdapterObject -Untagged | Out-Default
+}
+
+$arguments = @{
+    VMNetworkAdapter   = $VMNetworkAdapterObject
+    MacAddressSpoofing = $( if ( $vmnaProperties.MacAddressSpoofing ) { 'On' } else { 'Off' } )
+    DhcpGuard          = $( if ( $vmnaProperties.DhcpGuard ) { 'On' } else { 'Off' } )
+    RouterGuard        = $( if ( $vmnaProperties.RouterGuard ) { 'On' } else { 'Off' } )
+}
+
+if ( $vmnaProperties.DeviceNaming -ne ( [string]$VMNetworkAdapterObject.DeviceNaming -eq 'On' ) ) {   # only when changed, device naming isn't supported by a generation 1 vm
+    $arguments.DeviceNaming = $( if ( $vmnaProperties.DeviceNaming ) { 'On' } else { 'Off' } )
+}
+
+# the mac-address can only be changed when the vm is off
+if ( $vmnaProperties.MacAddress ) {   # only when changed, the mac-address is left untouched otherwise
+    $arguments.StaticMacAddress = $vmnaProperties.MacAddress -replace '[-:\.]', ''
+} elseif ( $vmnaProperties.DynamicMacAddress -and -not $VMNetworkAdapterObject.DynamicMacAddressEnabled ) {
+    $arguments.DynamicMacAddress = $true
+}
+
+Set-VMNetworkAdapter @arguments | Out-Default
+
+if ( $vmnaProperties.Name -and ( $vmnaProperties.Name -ne '{{.Name}}' ) ) {
+    Rename-VMNetworkAdapter -VMNetworkAdapter $VMNetworkAdapterObject -NewName $vmnaProperties.Name | Out-Default
+}
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteVMNetworkAdapter(c *HypervClient, vmna *VMNetworkAdapter) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err := runner.Run(c, deleteVMNetworkAdapterScript, deleteVMNetworkAdapterArguments{
+        VMName: vmna.VMName,
+        Name:   vmna.Name,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVMNetworkAdapter()] cannot delete network adapter %q for vm %q\n", vmna.Name, vmna.VMName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVMNetworkAdapter()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVMNetworkAdapter()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/deleteVMNetworkAdapter()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/deleteVMNetworkAdapter()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/deleteVMNetworkAdapter()] deleted network adapter %q for vm %q\n", vmna.Name, vmna.VMName)
+    return nil
+}
+
+type deleteVMNetworkAdapterArguments struct{
+    VMName string
+    Name   string
+}
+
+var deleteVMNetworkAdapterScript = script.New("deleteVMNetworkAdapter", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$VMObject = Get-VM -Name '{{.VMName}}' -ErrorAction 'Ignore'
+if ( -not $VMObject ) {
+    throw "cannot find vm '{{.VMName}}'"
+}
+
+$VMNetworkAdapterObject = Get-VMNetworkAdapter -VM $VMObject -Name '{{.Name}}' -ErrorAction 'Ignore'
+if ( -not $VMNetworkAdapterObject ) {
+    throw "cannot find network adapter '{{.Name}}' for vm '{{.VMName}}'"
+}
+
+Remove-VMNetworkAdapter -VMNetworkAdapter $VMNetworkAdapterObject | Out-Default
+`)
+
+//------------------------------------------------------------------------------
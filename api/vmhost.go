@@ -0,0 +1,88 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type VMHost struct {
+    Name                           string
+
+    IovSupport                     bool
+    IovSupportReasons              []string
+}
+
+//------------------------------------------------------------------------------
+
+func (c *HypervClient) ReadVMHost() (vmhost *VMHost, err error) {
+    return readVMHost(c)
+}
+
+//------------------------------------------------------------------------------
+
+func readVMHost(c *HypervClient) (vmhost *VMHost, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readVMHostScript, nil, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMHost()] cannot read vmhost\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMHost()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMHost()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMHost()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readVMHost()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to vmhost
+    vmhost = new(VMHost)
+    err = json.Unmarshal(stdout.Bytes(), vmhost)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMHost()] cannot convert json to 'vmhost'\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVMHost()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readVMHost()] read vmhost %q\n", vmhost.Name)
+    return vmhost, nil
+}
+
+var readVMHostScript = script.New("readVMHost", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$VMHostObject = Get-VMHost
+
+$VMHost = @{
+    Name              = $VMHostObject.Name
+    IovSupport        = $VMHostObject.IovSupport
+    IovSupportReasons = @( $VMHostObject.IovSupportReasons | Where-Object { $_ } )
+}
+
+Write-Output $( ConvertTo-Json -InputObject $VMHost )
+`)
+
+//------------------------------------------------------------------------------
@@ -32,6 +32,20 @@ type VSwitch struct {
     AllowManagementOS              bool
     NetAdapterName                 string
     NetAdapterInterfaceDescription string
+
+    // SR-IOV and packet direct             // can only be set when creating an "external" switch
+    EnableIov                      bool
+    EnablePacketDirect             bool
+
+    // bandwidth
+    MinimumBandwidthMode              string   // "" (default), "absolute", "weight" or "none" - can only be set when creating the switch
+    DefaultFlowMinimumBandwidthWeight int      // only used when MinimumBandwidthMode is "weight", 0 (default) when creating the switch is not configured
+
+    // computed
+    Id                             string   // the GUID of the vswitch
+    IovEnabled                     bool
+    IovSupportReasons              []string
+    PacketDirectEnabled            bool
 }
 
 //------------------------------------------------------------------------------
@@ -47,7 +61,7 @@ func (c *HypervClient) CreateVSwitch(vsProperties *VSwitch) error {
         return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVSwitch(vsProperties)] missing 'vsProperties.NetAdapterName' or 'vsProperties.NetAdapterInterfaceDescription' for \"external\" switch")
     }
 
-    return createVSwitch(c, vsProperties)
+    return createVSwitch(c, vsProperties, false)
 }
 
 func (c *HypervClient) ReadVSwitch(vs *VSwitch) (vswitch *VSwitch, err error) {
@@ -63,20 +77,57 @@ func (c *HypervClient) UpdateVSwitch(vs *VSwitch, vsProperties *VSwitch) error {
         return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/vs.Update(vsProperties)] missing 'vs.Name'")
     }
 
-    return updateVSwitch(c, vs, vsProperties)
+    return updateVSwitch(c, vs, vsProperties, false)
 }
 
-func (c *HypervClient) DeleteVSwitch(vs *VSwitch) error {
+// DeleteVSwitch fails when vm network adapters are connected to the vswitch, unless force is true
+func (c *HypervClient) DeleteVSwitch(vs *VSwitch, force bool) error {
     if vs.Name == "" {
         return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/vs.Delete()] missing 'vs.Name'")
     }
 
-    return deleteVSwitch(c, vs)
+    return deleteVSwitch(c, vs, force, false)
+}
+
+// the detached methods run the script in a scheduled task on the hyperv-server, this survives a disconnect of the provider's connection
+// remark that they return as soon as the scheduled task is started, errors in the script are only logged on the hyperv-server
+func (c *HypervClient) CreateVSwitchDetached(vsProperties *VSwitch) error {
+    if vsProperties.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVSwitchDetached(vsProperties)] missing 'vsProperties.Name'")
+    }
+    if vsProperties.SwitchType == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVSwitchDetached(vsProperties)] missing 'vsProperties.SwitchType'")
+    }
+    if strings.ToLower(vsProperties.SwitchType) != "private" && strings.ToLower(vsProperties.SwitchType) != "internal" && vsProperties.NetAdapterName == "" && vsProperties.NetAdapterInterfaceDescription == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/CreateVSwitchDetached(vsProperties)] missing 'vsProperties.NetAdapterName' or 'vsProperties.NetAdapterInterfaceDescription' for \"external\" switch")
+    }
+
+    return createVSwitch(c, vsProperties, true)
+}
+
+func (c *HypervClient) UpdateVSwitchDetached(vs *VSwitch, vsProperties *VSwitch) error {
+    if vs.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/vs.UpdateDetached(vsProperties)] missing 'vs.Name'")
+    }
+
+    return updateVSwitch(c, vs, vsProperties, true)
+}
+
+func (c *HypervClient) DeleteVSwitchDetached(vs *VSwitch, force bool) error {
+    if vs.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/vs.DeleteDetached()] missing 'vs.Name'")
+    }
+
+    return deleteVSwitch(c, vs, force, true)
+}
+
+func (c *HypervClient) ReadVSwitches() (vswitches []VSwitch, err error) {
+    return readVSwitches(c)
 }
 
 //------------------------------------------------------------------------------
 
-func createVSwitch(c *HypervClient, vsProperties *VSwitch) error {
+func createVSwitch(c *HypervClient, vsProperties *VSwitch, detached bool) error {
     // convert vsProperties to JSON
     vsPropertiesJSON, err := json.Marshal(vsProperties)
     if err != nil {
@@ -89,8 +140,12 @@ func createVSwitch(c *HypervClient, vsProperties *VSwitch) error {
     var stderr bytes.Buffer
 
     // run script
-    err = runner.Run(c, createVSwitchScript, createVSwitchArguments{
-        VSPropertiesJSON: string(vsPropertiesJSON),
+    run := runner.Run
+    if detached {
+        run = runDetached
+    }
+    err = run(c, createVSwitchScript, createVSwitchArguments{
+        VSPropertiesJSON: escapeSingleQuotes(string(vsPropertiesJSON)),
     }, &stdout, &stderr)
     if err != nil {
         var runnerErr runner.Error
@@ -136,14 +191,29 @@ if ( $vsProperties.SwitchType -and ( $vsProperties.SwitchType.ToLower() -eq "pri
     $arguments.SwitchType = [Microsoft.HyperV.PowerShell.VMSwitchType]$vsProperties.SwitchType
 } else {
     $arguments.AllowManagementOS = $vsProperties.AllowManagementOS
-    if ( $vsPropertiesNetAdapterName ) {
+    if ( $vsProperties.NetAdapterName ) {
         $arguments.NetAdapterName = $vsProperties.NetAdapterName
     } else {
         $arguments.NetAdapterInterfaceDescription = $vsProperties.NetAdapterInterfaceDescription
     }
+
+    if ( $vsProperties.EnableIov ) {
+        $arguments.EnableIov = $true
+    }
+    if ( $vsProperties.EnablePacketDirect ) {
+        $arguments.EnablePacketDirect = $true
+    }
+}
+
+if ( $vsProperties.MinimumBandwidthMode ) {
+    $arguments.MinimumBandwidthMode = [Microsoft.HyperV.PowerShell.VMSwitchBandwidthMode]$vsProperties.MinimumBandwidthMode
 }
 
-New-VMSwitch @arguments | Out-Default
+$VMSwitchObject = New-VMSwitch @arguments
+
+if ( ( [string]$VMSwitchObject.BandwidthReservationMode -eq 'Weight' ) -and ( $vsProperties.DefaultFlowMinimumBandwidthWeight -gt 0 ) ) {
+    Set-VMSwitch -VMSwitch $VMSwitchObject -DefaultFlowMinimumBandwidthWeight $vsProperties.DefaultFlowMinimumBandwidthWeight | Out-Default
+}
 `)
 
 //------------------------------------------------------------------------------
@@ -203,6 +273,14 @@ $VSwitch = @{
     SwitchType        = $( [string]$VMSwitchObject.SwitchType ).ToLower()
     Notes             = $VMSwitchObject.Notes
     AllowManagementOS = $VMSwitchObject.AllowManagementOS
+
+    MinimumBandwidthMode              = $( [string]$VMSwitchObject.BandwidthReservationMode ).ToLower()
+    DefaultFlowMinimumBandwidthWeight = [int]$VMSwitchObject.DefaultFlowMinimumBandwidthWeight
+
+    Id                  = [string]$VMSwitchObject.Id
+    IovEnabled          = $VMSwitchObject.IovEnabled
+    IovSupportReasons   = @( $VMSwitchObject.IovSupportReasons | Where-Object { $_ } )
+    PacketDirectEnabled = $VMSwitchObject.PacketDirectEnabled
 }
 
 if ( $VMSwitchObject.NetAdapterInterfaceDescription ) {
@@ -215,7 +293,7 @@ Write-Output $( ConvertTo-Json -InputObject $VSwitch )
 
 //------------------------------------------------------------------------------
 
-func updateVSwitch(c *HypervClient, vs *VSwitch, vsProperties *VSwitch) error {
+func updateVSwitch(c *HypervClient, vs *VSwitch, vsProperties *VSwitch, detached bool) error {
     // create buffer to capture stdout & stderr
     var stdout bytes.Buffer
     var stderr bytes.Buffer
@@ -227,9 +305,13 @@ func updateVSwitch(c *HypervClient, vs *VSwitch, vsProperties *VSwitch) error {
     }
 
     // run script
-    err = runner.Run(c, updateVSwitchScript, updateVSwitchArguments{
+    run := runner.Run
+    if detached {
+        run = runDetached
+    }
+    err = run(c, updateVSwitchScript, updateVSwitchArguments{
         Name:             vs.Name,
-        VSPropertiesJSON: string(vsPropertiesJSON),
+        VSPropertiesJSON: escapeSingleQuotes(string(vsPropertiesJSON)),
     }, &stdout, &stderr)
     if err != nil {
         var runnerErr runner.Error
@@ -284,19 +366,28 @@ if ( $vsProperties.SwitchType -and ( ( $vsProperties.SwitchType.ToLower() -eq "p
     }
 }
 
+if ( ( [string]$VMSwitchObject.BandwidthReservationMode -eq 'Weight' ) -and ( $VMSwitchObject.DefaultFlowMinimumBandwidthWeight -ne $vsProperties.DefaultFlowMinimumBandwidthWeight ) ) {
+    $arguments.DefaultFlowMinimumBandwidthWeight = $vsProperties.DefaultFlowMinimumBandwidthWeight
+}
+
 Set-VMSwitch @arguments | Out-Default
 `)
 
 //------------------------------------------------------------------------------
 
-func deleteVSwitch(c *HypervClient, vs *VSwitch) error {
+func deleteVSwitch(c *HypervClient, vs *VSwitch, force bool, detached bool) error {
     // create buffer to capture stdout & stderr
     var stdout bytes.Buffer
     var stderr bytes.Buffer
 
     // run script
-    err := runner.Run(c, deleteVSwitchScript, deleteVSwitchArguments{
-        Name: vs.Name,
+    run := runner.Run
+    if detached {
+        run = runDetached
+    }
+    err := run(c, deleteVSwitchScript, deleteVSwitchArguments{
+        Name:  vs.Name,
+        Force: force,
     }, &stdout, &stderr)
     if err != nil {
         var runnerErr runner.Error
@@ -319,7 +410,8 @@ func deleteVSwitch(c *HypervClient, vs *VSwitch) error {
 }
 
 type deleteVSwitchArguments struct{
-    Name string
+    Name  string
+    Force bool
 }
 
 var deleteVSwitchScript = script.New("deleteVSwitch", "powershell", `
@@ -331,7 +423,87 @@ if ( -not $VMSwitchObject ) {
     throw "cannot find vswitch '{{.Name}}'"
 }
 
+{{- if not .Force }}
+
+$VMNetworkAdapterObjects = @( Get-VMNetworkAdapter -VMName '*' | Where-Object { $_.SwitchName -eq $VMSwitchObject.Name } )
+if ( $VMNetworkAdapterObjects.Count -gt 0 ) {
+    $names = $( $VMNetworkAdapterObjects | ForEach-Object { "'$( $_.VMName )/$( $_.Name )'" } ) -join ', '
+    throw "cannot delete vswitch '{{.Name}}', vm network adapters are connected: $names"
+}
+{{- end }}
+
 Remove-VMSwitch -VMSwitch $VMSwitchObject -Force | Out-Default
 `)
 
 //------------------------------------------------------------------------------
+
+func readVSwitches(c *HypervClient) (vswitches []VSwitch, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readVSwitchesScript, nil, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitches()] cannot read vswitches\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitches()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitches()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitches()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readVSwitches()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to vswitches
+    err = json.Unmarshal(stdout.Bytes(), &vswitches)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitches()] cannot convert json to 'vswitches'\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitches()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readVSwitches()] read %d vswitches\n", len(vswitches))
+    return vswitches, nil
+}
+
+var readVSwitchesScript = script.New("readVSwitches", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$NetAdapterObjects = @( Get-NetAdapter )
+
+$VSwitches = @( Get-VMSwitch | ForEach-Object {
+    $VMSwitchObject = $_
+
+    $VSwitch = @{
+        Name              = $VMSwitchObject.Name
+        SwitchType        = $( [string]$VMSwitchObject.SwitchType ).ToLower()
+        Notes             = $VMSwitchObject.Notes
+        AllowManagementOS = $VMSwitchObject.AllowManagementOS
+
+        MinimumBandwidthMode              = $( [string]$VMSwitchObject.BandwidthReservationMode ).ToLower()
+        DefaultFlowMinimumBandwidthWeight = [int]$VMSwitchObject.DefaultFlowMinimumBandwidthWeight
+
+        Id                  = [string]$VMSwitchObject.Id
+        IovEnabled          = $VMSwitchObject.IovEnabled
+        IovSupportReasons   = @( $VMSwitchObject.IovSupportReasons | Where-Object { $_ } )
+        PacketDirectEnabled = $VMSwitchObject.PacketDirectEnabled
+    }
+
+    if ( $VMSwitchObject.NetAdapterInterfaceDescription ) {
+        $VSwitch.NetAdapterName                 = $( $NetAdapterObjects | Where-Object { $_.InterfaceDescription -eq $VMSwitchObject.NetAdapterInterfaceDescription } ).Name
+        $VSwitch.NetAdapterInterfaceDescription = $VMSwitchObject.NetAdapterInterfaceDescription
+    }
+
+    $VSwitch
+} )
+
+Write-Output $( ConvertTo-Json -InputObject $VSwitches -Depth 3 )
+`)
+
+//------------------------------------------------------------------------------
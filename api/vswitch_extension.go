@@ -0,0 +1,268 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type VSwitchExtension struct {
+    SwitchName                     string   // required, except when reading the extensions that are installed on the host
+    Name                           string   // required
+    Enabled                        bool
+
+    // computed
+    Running                        bool
+    Vendor                         string
+    Version                        string
+    ExtensionType                  string   // "capture", "filtering", "forwarding" or "monitoring"
+}
+
+//------------------------------------------------------------------------------
+
+func (c *HypervClient) ReadVSwitchExtension(vse *VSwitchExtension) (vswitchExtension *VSwitchExtension, err error) {
+    if vse.SwitchName == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/vse.Read()] missing 'vse.SwitchName'")
+    }
+    if vse.Name == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-hyperv/api/vse.Read()] missing 'vse.Name'")
+    }
+
+    return readVSwitchExtension(c, vse)
+}
+
+func (c *HypervClient) UpdateVSwitchExtension(vse *VSwitchExtension, vseProperties *VSwitchExtension) error {
+    if vse.SwitchName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/vse.Update(vseProperties)] missing 'vse.SwitchName'")
+    }
+    if vse.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-hyperv/api/vse.Update(vseProperties)] missing 'vse.Name'")
+    }
+
+    return updateVSwitchExtension(c, vse, vseProperties)
+}
+
+func (c *HypervClient) ReadVSwitchExtensions(switchName string) (vswitchExtensions []VSwitchExtension, err error) {
+    // when switchName is "", the extensions that are installed on the host are returned
+    return readVSwitchExtensions(c, switchName)
+}
+
+//------------------------------------------------------------------------------
+
+func readVSwitchExtension(c *HypervClient, vse *VSwitchExtension) (vswitchExtension *VSwitchExtension, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readVSwitchExtensionScript, readVSwitchExtensionArguments{
+        SwitchName: vse.SwitchName,
+        Name:       vse.Name,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitchExtension()] cannot read vswitch extension %q for vswitch %q\n", vse.Name, vse.SwitchName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitchExtension()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitchExtension()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitchExtension()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readVSwitchExtension()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to vswitchExtension
+    vswitchExtension = new(VSwitchExtension)
+    err = json.Unmarshal(stdout.Bytes(), vswitchExtension)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitchExtension()] cannot convert json to 'vswitchExtension' for %q\n", vse.Name)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitchExtension()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readVSwitchExtension()] read vswitch extension %q for vswitch %q\n", vse.Name, vse.SwitchName)
+    return vswitchExtension, nil
+}
+
+type readVSwitchExtensionArguments struct{
+    SwitchName string
+    Name       string
+}
+
+var readVSwitchExtensionScript = script.New("readVSwitchExtension", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+$VMSwitchObject = Get-VMSwitch -Name '{{.SwitchName}}' -ErrorAction 'Ignore'
+if ( -not $VMSwitchObject ) {
+    throw "cannot find vswitch '{{.SwitchName}}'"
+}
+
+$VMSwitchExtensionObject = Get-VMSwitchExtension -VMSwitch $VMSwitchObject -Name '{{.Name}}' -ErrorAction 'Ignore'
+if ( -not $VMSwitchExtensionObject ) {
+    throw "cannot find vswitch extension '{{.Name}}' for vswitch '{{.SwitchName}}'"
+}
+
+$VSwitchExtension = @{
+    SwitchName    = $VMSwitchExtensionObject.SwitchName
+    Name          = $VMSwitchExtensionObject.Name
+    Enabled       = $VMSwitchExtensionObject.Enabled
+    Running       = $VMSwitchExtensionObject.Running
+    Vendor        = $VMSwitchExtensionObject.Vendor
+    Version       = $VMSwitchExtensionObject.Version
+    ExtensionType = $( [string]$VMSwitchExtensionObject.ExtensionType ).ToLower()
+}
+
+Write-Output $( ConvertTo-Json -InputObject $VSwitchExtension )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateVSwitchExtension(c *HypervClient, vse *VSwitchExtension, vseProperties *VSwitchExtension) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err := runner.Run(c, updateVSwitchExtensionScript, updateVSwitchExtensionArguments{
+        SwitchName: vse.SwitchName,
+        Name:       vse.Name,
+        Enabled:    vseProperties.Enabled,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVSwitchExtension()] cannot update vswitch extension %q for vswitch %q\n", vse.Name, vse.SwitchName)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVSwitchExtension()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVSwitchExtension()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/updateVSwitchExtension()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/updateVSwitchExtension()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/updateVSwitchExtension()] updated vswitch extension %q for vswitch %q\n", vse.Name, vse.SwitchName)
+    return nil
+}
+
+type updateVSwitchExtensionArguments struct{
+    SwitchName string
+    Name       string
+    Enabled    bool
+}
+
+var updateVSwitchExtensionScript = script.New("updateVSwitchExtension", "powershell", `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+$VMSwitchObject = Get-VMSwitch -Name '{{.SwitchName}}' -ErrorAction 'Ignore'
+if ( -not $VMSwitchObject ) {
+    throw "cannot find vswitch '{{.SwitchName}}'"
+}
+
+$VMSwitchExtensionObject = Get-VMSwitchExtension -VMSwitch $VMSwitchObject -Name '{{.Name}}' -ErrorAction 'Ignore'
+if ( -not $VMSwitchExtensionObject ) {
+    throw "cannot find vswitch extension '{{.Name}}' for vswitch '{{.SwitchName}}'"
+}
+
+if ( ${{.Enabled}} ) {
+    Enable-VMSwitchExtension -VMSwitch $VMSwitchObject -Name '{{.Name}}' | Out-Default
+} else {
+    Disable-VMSwitchExtension -VMSwitch $VMSwitchObject -Name '{{.Name}}' | Out-Default
+}
+`)
+
+//------------------------------------------------------------------------------
+
+func readVSwitchExtensions(c *HypervClient, switchName string) (vswitchExtensions []VSwitchExtension, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    err = runner.Run(c, readVSwitchExtensionsScript, readVSwitchExtensionsArguments{
+        SwitchName: switchName,
+    }, &stdout, &stderr)
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitchExtensions()] cannot read vswitch extensions\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitchExtensions()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitchExtensions()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitchExtensions()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-hyperv/api/readVSwitchExtensions()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+
+    // convert stdout-JSON to vswitchExtensions
+    err = json.Unmarshal(stdout.Bytes(), &vswitchExtensions)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitchExtensions()] cannot convert json to 'vswitchExtensions'\n")
+        log.Printf("[ERROR][terraform-provider-hyperv/api/readVSwitchExtensions()] json: %s", stdout.String())
+        return nil, err
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv/api/readVSwitchExtensions()] read vswitch extensions\n")
+    return vswitchExtensions, nil
+}
+
+type readVSwitchExtensionsArguments struct{
+    SwitchName string
+}
+
+var readVSwitchExtensionsScript = script.New("readVSwitchExtensions", "powershell", `
+$ErrorActionPreference = 'Stop'
+
+if ( '{{.SwitchName}}' ) {
+    $VMSwitchObject = Get-VMSwitch -Name '{{.SwitchName}}' -ErrorAction 'Ignore'
+    if ( -not $VMSwitchObject ) {
+        throw "cannot find vswitch '{{.SwitchName}}'"
+    }
+
+    $VMSwitchExtensionObjects = Get-VMSwitchExtension -VMSwitch $VMSwitchObject
+} else {
+    $VMSwitchExtensionObjects = Get-VMSystemSwitchExtension
+}
+
+$VSwitchExtensions = @( $VMSwitchExtensionObjects | ForEach-Object {
+    @{
+        SwitchName    = '{{.SwitchName}}'
+        Name          = $_.Name
+        Enabled       = [bool]$_.Enabled
+        Running       = [bool]$_.Running
+        Vendor        = $_.Vendor
+        Version       = $_.Version
+        ExtensionType = $( [string]$_.ExtensionType ).ToLower()
+    }
+} )
+
+Write-Output $( ConvertTo-Json -InputObject $VSwitchExtensions )
+`)
+
+//------------------------------------------------------------------------------
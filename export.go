@@ -0,0 +1,338 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package main
+
+import (
+    "bytes"
+    "flag"
+    "fmt"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+    "unicode"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+)
+
+//------------------------------------------------------------------------------
+
+// export prints the terraform configuration and the import instructions for the existing hyperv objects
+//     the objects are read through the api package, the import IDs use the provider's id format, f.i. "//<host>/vswitches/<name>"
+//     when -name-prefix is set, only the objects with the prefix are exported, and the prefix is removed from the "name" in the configuration
+func export(args []string) int {
+    flags := flag.NewFlagSet("export", flag.ContinueOnError)
+    flags.Usage = func() {
+        fmt.Fprintf(flags.Output(), "Usage: terraform-provider-hyperv export [options]\n\n")
+        fmt.Fprintf(flags.Output(), "Prints the hyperv_* resource blocks and the import instructions for the existing hyperv objects.\n\n")
+        flags.PrintDefaults()
+    }
+
+    client       := connectionFlags(flags)
+    importFormat := flags.String("import-format", "block", "the format of the import instructions: \"block\" for terraform import blocks, \"command\" for terraform import commands")
+    if err := flags.Parse(args); err != nil {
+        return 2
+    }
+    if *importFormat != "block" && *importFormat != "command" {
+        fmt.Fprintf(os.Stderr, "Error: -import-format must be \"block\" or \"command\"\n")
+        return 2
+    }
+
+    c, err := client()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+        return 1
+    }
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    e := &exporter{
+        c:      c,
+        labels: make(map[string]bool),
+    }
+
+    // vswitches
+    vswitches, err := c.ReadVSwitches()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error: cannot read vswitches: %s\n", err)
+        return 1
+    }
+    sort.Slice(vswitches, func(i, j int) bool { return vswitches[i].Name < vswitches[j].Name })
+
+    vswitchLabels := make(map[string]string)
+    for _, vswitch := range vswitches {
+        name, ok := e.name(vswitch.Name)
+        if !ok {
+            continue
+        }
+        label := e.label("hyperv_vswitch", name)
+        vswitchLabels[strings.ToLower(vswitch.Name)] = label
+
+        notes, tags := api.SplitNotes(vswitch.Notes)
+
+        e.block("hyperv_vswitch", label)
+        e.attribute("name", hclString(name))
+        e.attribute("switch_type", hclString(vswitch.SwitchType))
+        if notes != "" {
+            e.attribute("notes", hclString(notes))
+        }
+        if len(tags) > 0 {
+            e.attribute("tags", hclMap(tags))
+        }
+        if vswitch.SwitchType == "external" {
+            e.attribute("allow_management_os", strconv.FormatBool(vswitch.AllowManagementOS))
+            e.attribute("net_adapter_name", hclString(vswitch.NetAdapterName))
+            if vswitch.EnableIov {
+                e.attribute("enable_iov", "true")
+            }
+            if vswitch.EnablePacketDirect {
+                e.attribute("enable_packet_direct", "true")
+            }
+        }
+        e.end("hyperv_vswitch", label, fmt.Sprintf("//%s/vswitches/%s", host, vswitch.Name))
+    }
+
+    // management-os adapters
+    managementOSAdapters, err := c.ReadManagementOSAdapters()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error: cannot read management-os adapters: %s\n", err)
+        return 1
+    }
+    sort.Slice(managementOSAdapters, func(i, j int) bool { return managementOSAdapters[i].Name < managementOSAdapters[j].Name })
+
+    for _, managementOSAdapter := range managementOSAdapters {
+        if strings.EqualFold(managementOSAdapter.Name, managementOSAdapter.SwitchName) {
+            continue   // the adapter created for "allow_management_os" of the vswitch
+        }
+        name, ok := e.name(managementOSAdapter.Name)
+        if !ok {
+            continue
+        }
+        label := e.label("hyperv_management_os_adapter", name)
+
+        e.block("hyperv_management_os_adapter", label)
+        if vswitchLabel, ok := vswitchLabels[strings.ToLower(managementOSAdapter.SwitchName)]; ok {
+            e.attribute("switch_name", fmt.Sprintf("hyperv_vswitch.%s.hyperv_name", vswitchLabel))
+        } else {
+            e.attribute("switch_name", hclString(managementOSAdapter.SwitchName))
+        }
+        e.attribute("name", hclString(name))
+        if managementOSAdapter.VlanId != 0 {
+            e.attribute("vlan_id", strconv.Itoa(managementOSAdapter.VlanId))
+        }
+        if managementOSAdapter.MinimumBandwidthWeight != 0 {
+            e.attribute("minimum_bandwidth_weight", strconv.Itoa(managementOSAdapter.MinimumBandwidthWeight))
+        }
+        e.end("hyperv_management_os_adapter", label, fmt.Sprintf("//%s/management-os-adapters/%s", host, managementOSAdapter.Name))
+    }
+
+    // nats
+    nats, err := c.ReadNats()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error: cannot read nats: %s\n", err)
+        return 1
+    }
+    sort.Slice(nats, func(i, j int) bool { return nats[i].Name < nats[j].Name })
+
+    natLabels := make(map[string]string)
+    for _, nat := range nats {
+        name, ok := e.name(nat.Name)
+        if !ok {
+            continue
+        }
+        label := e.label("hyperv_nat", name)
+        natLabels[strings.ToLower(nat.Name)] = label
+
+        e.block("hyperv_nat", label)
+        e.attribute("name", hclString(name))
+        e.attribute("internal_ip_interface_address_prefix", hclString(nat.InternalIPInterfaceAddressPrefix))
+        e.end("hyperv_nat", label, fmt.Sprintf("//%s/nats/%s", host, nat.Name))
+    }
+
+    // nat static mappings
+    natStaticMappings, err := c.ReadNatStaticMappings()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error: cannot read nat static mappings: %s\n", err)
+        return 1
+    }
+    sort.Slice(natStaticMappings, func(i, j int) bool { return natStaticMappings[i].StaticMappingID < natStaticMappings[j].StaticMappingID })
+
+    for _, natStaticMapping := range natStaticMappings {
+        natLabel, ok := natLabels[strings.ToLower(natStaticMapping.NatName)]
+        if !ok && c.NamePrefix != "" {
+            continue   // the nat is not exported
+        }
+        label := e.label("hyperv_nat_static_mapping", fmt.Sprintf("%s_%s_%d", natStaticMapping.NatName, natStaticMapping.Protocol, natStaticMapping.ExternalPort))
+
+        e.block("hyperv_nat_static_mapping", label)
+        if ok {
+            e.attribute("nat_name", fmt.Sprintf("hyperv_nat.%s.hyperv_name", natLabel))
+        } else {
+            e.attribute("nat_name", hclString(natStaticMapping.NatName))
+        }
+        e.attribute("protocol", hclString(natStaticMapping.Protocol))
+        e.attribute("external_ip_address", hclString(natStaticMapping.ExternalIPAddress))
+        e.attribute("external_port", strconv.Itoa(natStaticMapping.ExternalPort))
+        e.attribute("internal_ip_address", hclString(natStaticMapping.InternalIPAddress))
+        e.attribute("internal_port", strconv.Itoa(natStaticMapping.InternalPort))
+        e.end("hyperv_nat_static_mapping", label, fmt.Sprintf("//%s/nats/%s/static-mappings/%s/%s:%d", host, natStaticMapping.NatName, natStaticMapping.Protocol, natStaticMapping.ExternalIPAddress, natStaticMapping.ExternalPort))
+    }
+
+    // vms
+    vms, err := c.ReadVMs()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error: cannot read vms: %s\n", err)
+        return 1
+    }
+    sort.Slice(vms, func(i, j int) bool { return vms[i].Name < vms[j].Name })
+
+    for _, vm := range vms {
+        name, ok := e.name(vm.Name)
+        if !ok {
+            continue
+        }
+        label := e.label("hyperv_vm", name)
+
+        notes, tags := api.SplitNotes(vm.Notes)
+
+        e.block("hyperv_vm", label)
+        e.attribute("name", hclString(name))
+        e.attribute("generation", strconv.Itoa(vm.Generation))
+        e.attribute("version", hclString(vm.Version))
+        e.attribute("memory_startup_bytes", strconv.FormatInt(vm.MemoryStartupBytes, 10))
+        e.attribute("processor_count", strconv.Itoa(vm.ProcessorCount))
+        if notes != "" {
+            e.attribute("notes", hclString(notes))
+        }
+        if len(tags) > 0 {
+            e.attribute("tags", hclMap(tags))
+        }
+        e.end("hyperv_vm", label, fmt.Sprintf("//%s/vms/%s", host, vm.Name))
+    }
+
+    // print configuration, followed by the import instructions
+    fmt.Print(e.config.String())
+    if len(e.imports) > 0 && *importFormat == "block" {
+        for _, i := range e.imports {
+            fmt.Printf("\nimport {\n  to = %s\n  id = %s\n}\n", i.address, hclString(i.id))
+        }
+    }
+    if len(e.imports) > 0 && *importFormat == "command" {
+        fmt.Printf("\n# import the existing objects into the terraform state:\n")
+        for _, i := range e.imports {
+            fmt.Printf("#   terraform import %s %s\n", shellString(i.address), shellString(i.id))
+        }
+    }
+
+    fmt.Fprintf(os.Stderr, "Exported %d resources.\n", len(e.imports))
+    return 0
+}
+
+//------------------------------------------------------------------------------
+
+type exporter struct {
+    c       *api.HypervClient
+    labels  map[string]bool
+    config  bytes.Buffer
+    imports []exportImport
+}
+
+type exportImport struct {
+    address string   // f.i. "hyperv_vswitch.example"
+    id      string   // f.i. "//localhost/vswitches/example"
+}
+
+// name returns the name for the configuration, removing the provider's "name_prefix"
+// the objects without the prefix cannot be managed with this "name_prefix", and are not exported
+func (e *exporter) name(hypervName string) (string, bool) {
+    prefix := e.c.NamePrefix
+    if len(hypervName) < len(prefix) || !strings.EqualFold(hypervName[:len(prefix)], prefix) {
+        return "", false
+    }
+    return hypervName[len(prefix):], true
+}
+
+// label returns a unique terraform resource name for an object
+func (e *exporter) label(resourceType string, name string) string {
+    var b strings.Builder
+    for _, r := range strings.ToLower(name) {
+        if r < unicode.MaxASCII && ( unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' ) {
+            b.WriteRune(r)
+        } else {
+            b.WriteRune('_')
+        }
+    }
+
+    label := b.String()
+    if label == "" || unicode.IsDigit(rune(label[0])) || label[0] == '-' {
+        label = "_" + label
+    }
+
+    unique := label
+    for i := 2; e.labels[resourceType + "." + unique]; i++ {
+        unique = fmt.Sprintf("%s_%d", label, i)
+    }
+    e.labels[resourceType + "." + unique] = true
+
+    return unique
+}
+
+func (e *exporter) block(resourceType string, label string) {
+    if e.config.Len() > 0 {
+        e.config.WriteString("\n")
+    }
+    fmt.Fprintf(&e.config, "resource %q %q {\n", resourceType, label)
+}
+
+func (e *exporter) attribute(name string, value string) {
+    fmt.Fprintf(&e.config, "  %s = %s\n", name, value)
+}
+
+func (e *exporter) end(resourceType string, label string, id string) {
+    e.config.WriteString("}\n")
+
+    e.imports = append(e.imports, exportImport{
+        address: resourceType + "." + label,
+        id:      id,
+    })
+}
+
+//------------------------------------------------------------------------------
+
+// hclString returns a quoted HCL string, escaping the template sequences
+func hclString(s string) string {
+    s = strconv.Quote(s)
+    s = strings.Replace(s, "${", "$${", -1)
+    s = strings.Replace(s, "%{", "%%{", -1)
+    return s
+}
+
+// shellString returns a single-quoted shell string
+func shellString(s string) string {
+    return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+func hclMap(m map[string]string) string {
+    keys := make([]string, 0, len(m))
+    for k := range m {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+
+    var b strings.Builder
+    b.WriteString("{\n")
+    for _, k := range keys {
+        fmt.Fprintf(&b, "    %s = %s\n", hclString(k), hclString(m[k]))
+    }
+    b.WriteString("  }")
+    return b.String()
+}
+
+//------------------------------------------------------------------------------
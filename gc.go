@@ -0,0 +1,180 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package main
+
+import (
+    "flag"
+    "fmt"
+    "os"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+)
+
+//------------------------------------------------------------------------------
+
+// gc deletes the orphaned hyperv objects, vms and vswitches, using the ownership tags written by the provider
+//     an object is orphaned when its "ttl" tag is expired, or when its "workspace" tag is not one of the existing workspaces
+//     objects without ownership tags are never deleted
+// without -apply, gc only prints the plan
+func gc(args []string) int {
+    flags := flag.NewFlagSet("gc", flag.ContinueOnError)
+    flags.Usage = func() {
+        fmt.Fprintf(flags.Output(), "Usage: terraform-provider-hyperv gc [options]\n\n")
+        fmt.Fprintf(flags.Output(), "Deletes the hyperv objects with an expired ttl tag, or with a workspace tag for a workspace that no longer exists.\n\n")
+        flags.PrintDefaults()
+    }
+
+    client       := connectionFlags(flags)
+    apply        := flags.Bool("apply", false, "delete the orphaned objects, without this only the plan is printed")
+    force        := flags.Bool("force", false, "delete vswitches even when vm network adapters are connected")
+    ttlTag       := flags.String("ttl-tag", "ttl", "the tag with the expiry time of an object, f.i. \"2019-12-31T23:59:59Z\" or \"2019-12-31\"")
+    workspaceTag := flags.String("workspace-tag", "workspace", "the tag with the terraform workspace of an object")
+    workspaces   := flags.String("workspaces", "", "comma-separated list of the existing terraform workspaces, when empty the workspace tag is not checked")
+    if err := flags.Parse(args); err != nil {
+        return 2
+    }
+
+    c, err := client()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+        return 1
+    }
+
+    existingWorkspaces := make(map[string]bool)
+    for _, w := range strings.Split(*workspaces, ",") {
+        if w = strings.TrimSpace(w); w != "" {
+            existingWorkspaces[w] = true
+        }
+    }
+
+    now := time.Now()
+    orphaned := func(tags map[string]string) string {
+        if ttl, ok := tags[*ttlTag]; ok {
+            expiry, err := parseTTL(ttl)
+            if err != nil {
+                fmt.Fprintf(os.Stderr, "Warning: cannot parse %s tag %q: %s\n", *ttlTag, ttl, err)
+            } else if now.After(expiry) {
+                return fmt.Sprintf("%s %q expired", *ttlTag, ttl)
+            }
+        }
+        if workspace, ok := tags[*workspaceTag]; ok && len(existingWorkspaces) > 0 && !existingWorkspaces[workspace] {
+            return fmt.Sprintf("%s %q doesn't exist", *workspaceTag, workspace)
+        }
+        return ""
+    }
+
+    // find orphaned vms
+    vms, err := c.ReadVMs()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error: cannot read vms: %s\n", err)
+        return 1
+    }
+    sort.Slice(vms, func(i, j int) bool { return vms[i].Name < vms[j].Name })
+
+    var plan []gcObject
+    for _, vm := range vms {
+        _, tags := api.SplitNotes(vm.Notes)
+        reason := orphaned(tags)
+        if reason == "" {
+            continue
+        }
+
+        fmt.Printf("- delete hyperv_vm %q: %s\n", vm.Name, reason)
+        plan = append(plan, gcObject{ Type: "hyperv_vm", Name: vm.Name })
+    }
+
+    // find orphaned vswitches
+    vswitches, err := c.ReadVSwitches()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error: cannot read vswitches: %s\n", err)
+        return 1
+    }
+    sort.Slice(vswitches, func(i, j int) bool { return vswitches[i].Name < vswitches[j].Name })
+
+    // when the management connection cannot be determined, any vswitch with a host interface could carry it
+    managementKnown := true
+    managementVSwitchName := ""
+    if managementConnection, err := c.ReadManagementConnection(); err == nil {
+        managementVSwitchName = managementConnection.VSwitchName
+    } else {
+        fmt.Fprintf(os.Stderr, "Warning: cannot determine the management connection to the hyperv-server, skipping the \"external\" and \"internal\" vswitches: %s\n", err)
+        managementKnown = false
+    }
+
+    for _, vswitch := range vswitches {
+        _, tags := api.SplitNotes(vswitch.Notes)
+        reason := orphaned(tags)
+        if reason == "" {
+            continue
+        }
+        if managementVSwitchName != "" && strings.EqualFold(vswitch.Name, managementVSwitchName) {
+            fmt.Printf("  skip   hyperv_vswitch %q: %s, but it carries the management connection to the hyperv-server\n", vswitch.Name, reason)
+            continue
+        }
+        if !managementKnown && strings.ToLower(vswitch.SwitchType) != "private" {
+            fmt.Printf("  skip   hyperv_vswitch %q: %s, but it could carry the management connection to the hyperv-server\n", vswitch.Name, reason)
+            continue
+        }
+
+        fmt.Printf("- delete hyperv_vswitch %q: %s\n", vswitch.Name, reason)
+        plan = append(plan, gcObject{ Type: "hyperv_vswitch", Name: vswitch.Name })
+    }
+
+    fmt.Printf("\nPlan: %d to delete.\n", len(plan))
+    if !*apply || len(plan) == 0 {
+        return 0
+    }
+
+    // delete orphaned objects, the vms are deleted before the vswitches they are connected to
+    failed := 0
+    for _, object := range plan {
+        var err error
+        switch object.Type {
+        case "hyperv_vm":
+            v := new(api.VM)
+            v.Name = object.Name
+
+            err = c.DeleteVM(v)
+        case "hyperv_vswitch":
+            vs := new(api.VSwitch)
+            vs.Name = object.Name
+
+            err = c.DeleteVSwitch(vs, *force)
+        }
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "Error: cannot delete %s %q: %s\n", object.Type, object.Name, err)
+            failed++
+            continue
+        }
+        fmt.Printf("deleted %s %q\n", object.Type, object.Name)
+    }
+
+    fmt.Printf("\nApply complete! Resources: %d deleted, %d failed.\n", len(plan) - failed, failed)
+    if failed > 0 {
+        return 1
+    }
+    return 0
+}
+
+// gcObject is an orphaned hyperv object in the plan
+type gcObject struct {
+    Type string   // the terraform resource type, f.i. "hyperv_vswitch"
+    Name string   // the real name of the hyperv object
+}
+
+// parseTTL parses the expiry time from a "ttl" tag, as a RFC3339 timestamp or as a date
+func parseTTL(ttl string) (time.Time, error) {
+    if t, err := time.Parse(time.RFC3339, ttl); err == nil {
+        return t, nil
+    }
+    return time.Parse("2006-01-02", ttl)
+}
+
+//------------------------------------------------------------------------------
@@ -23,6 +23,10 @@ type Config struct {
     User     string
     Password string
     Insecure bool
+
+    // defaults for resources
+    DefaultTags map[string]string
+    NamePrefix  string
 }
 
 //------------------------------------------------------------------------------
@@ -43,6 +47,9 @@ func (c *Config) Client() (interface {}, error) {
                     [INFO][terraform-provider-hyperv]     insecure: %t
 `       , c.Type, c.Host, c.Port, c.User, c.Insecure)
     }
+    log.Printf(`[INFO][terraform-provider-hyperv]     default_tags: %#v
+                    [INFO][terraform-provider-hyperv]     name_prefix: %q
+`   , c.DefaultTags, c.NamePrefix)
 
     hypervClient := new(api.HypervClient)
     switch c.Type {
@@ -56,6 +63,8 @@ func (c *Config) Client() (interface {}, error) {
         hypervClient.Password = c.Password
         hypervClient.Insecure = c.Insecure
     }
+    hypervClient.DefaultTags = c.DefaultTags
+    hypervClient.NamePrefix  = c.NamePrefix
 
     log.Printf("[INFO][terraform-provider-hyperv] configured hyperv-provider\n")
     return hypervClient, nil
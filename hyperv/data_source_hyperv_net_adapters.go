@@ -0,0 +1,145 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+)
+
+//------------------------------------------------------------------------------
+
+func dataSourceHypervNetAdapters () *schema.Resource {
+    return &schema.Resource{
+        ReadContext: dataSourceHypervNetAdaptersRead,
+
+        Schema: map[string]*schema.Schema{
+            // filters
+            "physical_only": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+            },
+            "up_only": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+            },
+
+            // computed
+            "net_adapters": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "name": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "interface_description": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "interface_index": &schema.Schema{
+                            Type:     schema.TypeInt,
+                            Computed: true,
+                        },
+                        "mac_address": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "link_speed": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "status": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "vlan_id": &schema.Schema{
+                            Type:     schema.TypeInt,
+                            Computed: true,
+                        },
+                        "physical": &schema.Schema{
+                            Type:     schema.TypeBool,
+                            Computed: true,
+                        },
+                        "vswitch_name": &schema.Schema{            // "" when not bound to a vswitch
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func dataSourceHypervNetAdaptersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id           := fmt.Sprintf("//%s/net-adapters", host)
+    physicalOnly := d.Get("physical_only").(bool)
+    upOnly       := d.Get("up_only").(bool)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] reading hyperv_net_adapters %q
+                    [INFO][terraform-provider-hyperv]     physical_only: %#v
+                    [INFO][terraform-provider-hyperv]     up_only:       %#v
+`   , id, physicalOnly, upOnly)
+
+    // read net adapters
+    netAdapters, err := c.ReadNetAdapters()
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot read hyperv_net_adapters %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // filter net adapters
+    l := make([]map[string]interface{}, 0, len(netAdapters))
+    for _, netAdapter := range netAdapters {
+        if physicalOnly && !netAdapter.Physical {
+            continue
+        }
+        if upOnly && netAdapter.Status != "up" {
+            continue
+        }
+
+        l = append(l, map[string]interface{}{
+            "name":                  netAdapter.Name,
+            "interface_description": netAdapter.InterfaceDescription,
+            "interface_index":       netAdapter.InterfaceIndex,
+            "mac_address":           netAdapter.MacAddress,
+            "link_speed":            netAdapter.LinkSpeed,
+            "status":                netAdapter.Status,
+            "vlan_id":               netAdapter.VlanID,
+            "physical":              netAdapter.Physical,
+            "vswitch_name":          netAdapter.VSwitchName,
+        })
+    }
+
+    // set properties
+    d.Set("net_adapters", l)
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] read hyperv_net_adapters %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
@@ -7,11 +7,13 @@
 package hyperv
 
 import (
+    "context"
     "fmt"
     "log"
     "strings"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
     "github.com/stefaanc/terraform-provider-hyperv/api"
     "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
@@ -20,24 +22,34 @@ import (
 //------------------------------------------------------------------------------
 
 func dataSourceHypervVSwitch () *schema.Resource {
-    return &schema.Resource{
-        Read:   dataSourceHypervVSwitchRead,
+    return tfutil.WithDataSourceXLifecycle(&schema.Resource{
+        ReadContext: dataSourceHypervVSwitchRead,
 
         Schema: map[string]*schema.Schema{
             "name": &schema.Schema{
                 Type:     schema.TypeString,
                 Required: true,
             },
-            "switch_type": &schema.Schema{
+            "use_name_prefix": &schema.Schema{                     // resolve the name through the provider's "name_prefix"
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+            },
+
+            // computed
+            "hyperv_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "switch_type": &schema.Schema{   // lower case, as read from the infrastructure
                 Type:     schema.TypeString,
                 Computed: true,
-
-                StateFunc:        tfutil.StateToLower(),
             },
             "notes": &schema.Schema{
                 Type:     schema.TypeString,
                 Computed: true,
             },
+            "tags": tagsComputedSchema(),
             "allow_management_os": &schema.Schema{
                 Type:     schema.TypeBool,
                 Computed: true,
@@ -50,14 +62,26 @@ func dataSourceHypervVSwitch () *schema.Resource {
                 Type:     schema.TypeString,
                 Computed: true,
             },
+            "iov_enabled": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "iov_support_reasons": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem:     &schema.Schema{ Type: schema.TypeString },
+            },
 
             // lifecycle customizations that are not supported by the 'lifecycle' meta-argument for data sources
             "x_lifecycle": &tfutil.DataSourceXLifecycleSchema,
         },
-    }
+    }, &tfutil.DataSourceXLifecycle{
+        TypeName: "hyperv_vswitch",
+        ID:       dataSourceHypervVSwitchID,
+    })
 }
 
-func dataSourceHypervVSwitchRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceHypervVSwitchRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
     c := m.(*api.HypervClient)
 
     host := "localhost"
@@ -65,9 +89,11 @@ func dataSourceHypervVSwitchRead(d *schema.ResourceData, m interface{}) error {
         host = c.Host
     }
 
-    id          := fmt.Sprintf("//%s/vswitches/%s", host, d.Get("name").(string))
-    name        := d.Get("name").(string)
-    x_lifecycle := tfutil.GetResourceDataMap(d, "x_lifecycle")
+    name := d.Get("name").(string)
+    if d.Get("use_name_prefix").(bool) {
+        name = newHypervName(m, name)
+    }
+    id := fmt.Sprintf("//%s/vswitches/%s", host, name)
 
     log.Printf("[INFO][terraform-provider-hyperv] reading hyperv_vswitch %q\n", id)
 
@@ -77,50 +103,26 @@ func dataSourceHypervVSwitchRead(d *schema.ResourceData, m interface{}) error {
 
     vswitch, err := c.ReadVSwitch(vs)
     if err != nil {
-        // lifecycle customizations: ignore_error_if_not_exists
-        if x_lifecycle != nil {
-            ignore_error_if_not_exists := x_lifecycle["ignore_error_if_not_exists"].(bool)
-            if ignore_error_if_not_exists && strings.Contains(err.Error(), "cannot find vswitch") {
-                log.Printf("[INFO][terraform-provider-hyperv] cannot read hyperv_vswitch %q\n", id)
-
-                // set zeroed properties
-                d.Set("name", "")
-                d.Set("switch_type", "")
-                d.Set("notes", "")
-                d.Set("allow_management_os", false)
-                d.Set("net_adapter_name", "")
-                d.Set("net_adapter_interface_description", "")
-
-                // set computed lifecycle properties
-                x_lifecycle["exists"] = false
-                tfutil.SetResourceDataMap(d, "x_lifecycle", x_lifecycle)
-
-                // set id
-                d.SetId(id)
-
-                log.Printf("[INFO][terraform-provider-hyperv] ignored error and added zeroed hyperv_vswitch %q to terraform state\n", id)
-                return nil
-            }
-        }
-
-        // no lifecycle customizations
         log.Printf("[ERROR][terraform-provider-hyperv] cannot read hyperv_vswitch %q\n", id)
-        return err
+        return diag.FromErr(err)
     }
 
     // set properties
-    d.Set("name", vswitch.Name)
+    if d.Get("use_name_prefix").(bool) {
+        d.Set("name", terraformName(m, vswitch.Name))
+    } else {
+        d.Set("name", vswitch.Name)
+    }
+    d.Set("hyperv_name", vswitch.Name)
     d.Set("switch_type", strings.ToLower(vswitch.SwitchType))
-    d.Set("notes", vswitch.Notes)
+    notes, tags := api.SplitNotes(vswitch.Notes)
+    d.Set("notes", notes)
+    d.Set("tags", tags)
     d.Set("allow_management_os", vswitch.AllowManagementOS)
     d.Set("net_adapter_name", vswitch.NetAdapterName)
     d.Set("net_adapter_interface_description", vswitch.NetAdapterInterfaceDescription)
-
-    // set computed lifecycle properties
-    if x_lifecycle != nil {
-        x_lifecycle["exists"] = true
-        tfutil.SetResourceDataMap(d, "x_lifecycle", x_lifecycle)
-    }
+    d.Set("iov_enabled", vswitch.IovEnabled)
+    d.Set("iov_support_reasons", vswitch.IovSupportReasons)
 
     // set id
     d.SetId(id)
@@ -129,4 +131,19 @@ func dataSourceHypervVSwitchRead(d *schema.ResourceData, m interface{}) error {
     return nil
 }
 
+func dataSourceHypervVSwitchID(d *schema.ResourceData, m interface{}) string {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    name := d.Get("name").(string)
+    if d.Get("use_name_prefix").(bool) {
+        name = newHypervName(m, name)
+    }
+    return fmt.Sprintf("//%s/vswitches/%s", host, name)
+}
+
 //------------------------------------------------------------------------------
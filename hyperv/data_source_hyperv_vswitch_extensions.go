@@ -0,0 +1,115 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+)
+
+//------------------------------------------------------------------------------
+
+func dataSourceHypervVSwitchExtensions () *schema.Resource {
+    return &schema.Resource{
+        ReadContext: dataSourceHypervVSwitchExtensionsRead,
+
+        Schema: map[string]*schema.Schema{
+            "switch_name": &schema.Schema{                         // when not configured, the extensions installed on the hyperv-server are listed
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+            },
+
+            // computed
+            "extensions": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "name": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "vendor": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "version": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "extension_type": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "enabled": &schema.Schema{                 // always false when "switch_name" is not configured
+                            Type:     schema.TypeBool,
+                            Computed: true,
+                        },
+                        "running": &schema.Schema{                 // always false when "switch_name" is not configured
+                            Type:     schema.TypeBool,
+                            Computed: true,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func dataSourceHypervVSwitchExtensionsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    switchName := d.Get("switch_name").(string)
+
+    id := fmt.Sprintf("//%s/vswitch-extensions", host)
+    if switchName != "" {
+        id = fmt.Sprintf("//%s/vswitches/%s/extensions", host, switchName)
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv] reading hyperv_vswitch_extensions %q\n", id)
+
+    // read vswitch extensions
+    vswitchExtensions, err := c.ReadVSwitchExtensions(switchName)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot read hyperv_vswitch_extensions %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set properties
+    extensions := make([]map[string]interface{}, 0, len(vswitchExtensions))
+    for _, vswitchExtension := range vswitchExtensions {
+        extensions = append(extensions, map[string]interface{}{
+            "name":           vswitchExtension.Name,
+            "vendor":         vswitchExtension.Vendor,
+            "version":        vswitchExtension.Version,
+            "extension_type": vswitchExtension.ExtensionType,
+            "enabled":        vswitchExtension.Enabled,
+            "running":        vswitchExtension.Running,
+        })
+    }
+    d.Set("extensions", extensions)
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] read hyperv_vswitch_extensions %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,188 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "regexp"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+    "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func dataSourceHypervVSwitches () *schema.Resource {
+    return &schema.Resource{
+        ReadContext: dataSourceHypervVSwitchesRead,
+
+        Schema: map[string]*schema.Schema{
+            // filters
+            "switch_type": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+
+                ValidateFunc:     validation.StringInSlice([]string{ "", "private", "internal", "external" }, true),
+                StateFunc:        tfutil.StateToLower(),
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "name_regex": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+
+                ValidateFunc: validation.StringIsValidRegExp,
+            },
+            "net_adapter_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+            },
+            "net_adapter_interface_description": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+            },
+
+            // computed
+            "names": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem:     &schema.Schema{ Type: schema.TypeString },
+            },
+            "vswitches": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "name": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "switch_type": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "notes": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "tags": tagsComputedSchema(),
+                        "allow_management_os": &schema.Schema{
+                            Type:     schema.TypeBool,
+                            Computed: true,
+                        },
+                        "net_adapter_name": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "net_adapter_interface_description": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "iov_enabled": &schema.Schema{
+                            Type:     schema.TypeBool,
+                            Computed: true,
+                        },
+                        "iov_support_reasons": &schema.Schema{
+                            Type:     schema.TypeList,
+                            Computed: true,
+                            Elem:     &schema.Schema{ Type: schema.TypeString },
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func dataSourceHypervVSwitchesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id                             := fmt.Sprintf("//%s/vswitches", host)
+    switchType                     := strings.ToLower(d.Get("switch_type").(string))
+    nameRegex                      := d.Get("name_regex").(string)
+    netAdapterName                 := d.Get("net_adapter_name").(string)
+    netAdapterInterfaceDescription := d.Get("net_adapter_interface_description").(string)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] reading hyperv_vswitches %q
+                    [INFO][terraform-provider-hyperv]     switch_type:                       %#v
+                    [INFO][terraform-provider-hyperv]     name_regex:                        %#v
+                    [INFO][terraform-provider-hyperv]     net_adapter_name:                  %#v
+                    [INFO][terraform-provider-hyperv]     net_adapter_interface_description: %#v
+`   , id, switchType, nameRegex, netAdapterName, netAdapterInterfaceDescription)
+
+    var re *regexp.Regexp
+    if nameRegex != "" {
+        re = regexp.MustCompile(nameRegex)   // already validated
+    }
+
+    // read vswitches
+    vswitches, err := c.ReadVSwitches()
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot read hyperv_vswitches %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // filter vswitches
+    names := make([]string, 0, len(vswitches))
+    l     := make([]map[string]interface{}, 0, len(vswitches))
+    for _, vswitch := range vswitches {
+        if switchType != "" && strings.ToLower(vswitch.SwitchType) != switchType {
+            continue
+        }
+        if re != nil && !re.MatchString(vswitch.Name) {
+            continue
+        }
+        if netAdapterName != "" && !strings.EqualFold(vswitch.NetAdapterName, netAdapterName) {
+            continue
+        }
+        if netAdapterInterfaceDescription != "" && vswitch.NetAdapterInterfaceDescription != netAdapterInterfaceDescription {
+            continue
+        }
+
+        notes, tags := api.SplitNotes(vswitch.Notes)
+
+        names = append(names, vswitch.Name)
+        l = append(l, map[string]interface{}{
+            "name":                              vswitch.Name,
+            "switch_type":                       strings.ToLower(vswitch.SwitchType),
+            "notes":                             notes,
+            "tags":                              tags,
+            "allow_management_os":               vswitch.AllowManagementOS,
+            "net_adapter_name":                  vswitch.NetAdapterName,
+            "net_adapter_interface_description": vswitch.NetAdapterInterfaceDescription,
+            "iov_enabled":                       vswitch.IovEnabled,
+            "iov_support_reasons":               vswitch.IovSupportReasons,
+        })
+    }
+
+    // set properties
+    d.Set("names", names)
+    d.Set("vswitches", l)
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] read hyperv_vswitches %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,74 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "fmt"
+    "regexp"
+    "strings"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+)
+
+//------------------------------------------------------------------------------
+
+// parseImportID returns the name part of an import ID
+//     the import ID is either the real name of the hyperv object, or the id of the resource "//<host>/<kind>/<name>", f.i. copied from another terraform state or printed by the "export" subcommand
+// the host part of an id must match the provider's connection, to avoid importing an object from another hyperv-server with a wrong id
+// the id of a sub-resource, f.i. "//<host>/vms/<vm_name>/network-adapters/<name>", is not a valid id for its parent
+func parseImportID(c *api.HypervClient, importID string, kind string) (string, error) {
+    name, err := parseImportPath(c, importID, kind)
+    if err != nil {
+        return "", err
+    }
+
+    if strings.HasPrefix(importID, "//") && strings.Contains(name, "/") {
+        host := "localhost"
+        if c.Type != "local" {
+            host = c.Host
+        }
+
+        return "", fmt.Errorf("[terraform-provider-hyperv/hyperv/parseImportID()] invalid import ID %q, expected \"//%s/%s/<name>\"", importID, host, kind)
+    }
+
+    return name, nil
+}
+
+// parseImportPath returns the path part of an import ID, the same as parseImportID, but the path can have more than one segment
+//     f.i. the path of a vhd, or "<vm_name>/network-adapters/<name>" for the id of a sub-resource
+func parseImportPath(c *api.HypervClient, importID string, kind string) (string, error) {
+    if !strings.HasPrefix(importID, "//") {
+        return importID, nil
+    }
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    parts := strings.SplitN(importID[2:], "/", 3)   // "<host>", "<kind>", "<path>"
+    if len(parts) != 3 || parts[2] == "" {
+        return "", fmt.Errorf("[terraform-provider-hyperv/hyperv/parseImportPath()] invalid import ID %q, expected \"//%s/%s/<path>\"", importID, host, kind)
+    }
+    if parts[1] != kind {
+        return "", fmt.Errorf("[terraform-provider-hyperv/hyperv/parseImportPath()] invalid import ID %q, expected \"//%s/%s/<path>\"", importID, host, kind)
+    }
+    if !strings.EqualFold(parts[0], host) {
+        return "", fmt.Errorf("[terraform-provider-hyperv/hyperv/parseImportPath()] import ID %q is for host %q, but the provider is connected to host %q", importID, parts[0], host)
+    }
+
+    return parts[2], nil
+}
+
+var guidRegexp = regexp.MustCompile(`^\{?[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\}?$`)
+
+// isGUID returns true when an import ID is a GUID, f.i. "{5c3d0e4f-8b7a-4f3e-9d2c-1a0b9c8d7e6f}"
+func isGUID(importID string) bool {
+    return guidRegexp.MatchString(importID)
+}
+
+//------------------------------------------------------------------------------
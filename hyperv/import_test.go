@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "testing"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+)
+
+//------------------------------------------------------------------------------
+
+func TestParseImportID(t *testing.T) {
+    local := new(api.HypervClient)
+    local.Type = "local"
+
+    tests := []struct {
+        c           *api.HypervClient
+        importID    string
+        expected    string
+        expectError bool
+    }{
+        { testClient(""), "my-switch", "my-switch", false },
+        { testClient(""), "{5c3d0e4f-8b7a-4f3e-9d2c-1a0b9c8d7e6f}", "{5c3d0e4f-8b7a-4f3e-9d2c-1a0b9c8d7e6f}", false },
+        { testClient(""), "//test-host/vswitches/my-switch", "my-switch", false },
+        { testClient(""), "//TEST-HOST/vswitches/my switch", "my switch", false },
+        { testClient(""), "//test-host/vswitches/my-switch/extensions/my-extension", "", true },   // the id of a sub-resource
+        { testClient(""), "//other-host/vswitches/my-switch", "", true },
+        { testClient(""), "//test-host/nats/my-switch", "", true },
+        { testClient(""), "//test-host/vswitches/", "", true },
+        { testClient(""), "//test-host/vswitches", "", true },
+        { local, "//localhost/vswitches/my-switch", "my-switch", false },
+        { local, "//test-host/vswitches/my-switch", "", true },
+    }
+
+    for _, test := range tests {
+        actual, err := parseImportID(test.c, test.importID, "vswitches")
+        if test.expectError {
+            if err == nil {
+                t.Errorf("parseImportID(%q): expected an error, got %q", test.importID, actual)
+            }
+            continue
+        }
+        if err != nil {
+            t.Errorf("parseImportID(%q): unexpected error: %s", test.importID, err)
+            continue
+        }
+        if actual != test.expected {
+            t.Errorf("parseImportID(%q): expected %q, got %q", test.importID, test.expected, actual)
+        }
+    }
+}
+
+func TestParseImportPath(t *testing.T) {
+    tests := []struct {
+        importID    string
+        expected    string
+        expectError bool
+    }{
+        { "my-vm/eth0", "my-vm/eth0", false },
+        { "//test-host/vms/my-vm", "my-vm", false },
+        { "//test-host/vms/my-vm/network-adapters/eth0", "my-vm/network-adapters/eth0", false },
+        { "//other-host/vms/my-vm/network-adapters/eth0", "", true },
+        { "//test-host/vswitches/my-vm/network-adapters/eth0", "", true },
+        { "//test-host/vms/", "", true },
+    }
+
+    for _, test := range tests {
+        actual, err := parseImportPath(testClient(""), test.importID, "vms")
+        if test.expectError {
+            if err == nil {
+                t.Errorf("parseImportPath(%q): expected an error, got %q", test.importID, actual)
+            }
+            continue
+        }
+        if err != nil {
+            t.Errorf("parseImportPath(%q): unexpected error: %s", test.importID, err)
+            continue
+        }
+        if actual != test.expected {
+            t.Errorf("parseImportPath(%q): expected %q, got %q", test.importID, test.expected, actual)
+        }
+    }
+}
+
+func TestIsGUID(t *testing.T) {
+    tests := []struct {
+        importID string
+        expected bool
+    }{
+        { "5c3d0e4f-8b7a-4f3e-9d2c-1a0b9c8d7e6f", true },
+        { "{5C3D0E4F-8B7A-4F3E-9D2C-1A0B9C8D7E6F}", true },
+        { "5c3d0e4f-8b7a-4f3e-9d2c", false },
+        { "my-switch", false },
+    }
+
+    for _, test := range tests {
+        if actual := isGUID(test.importID); actual != test.expected {
+            t.Errorf("isGUID(%q): expected %t, got %t", test.importID, test.expected, actual)
+        }
+    }
+}
+
+//------------------------------------------------------------------------------
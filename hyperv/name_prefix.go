@@ -0,0 +1,93 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+)
+
+//------------------------------------------------------------------------------
+
+// the provider's "name_prefix" is added to the "name" of the managed hyperv objects
+//     the "name" argument is the name in the config, without the prefix
+//     the computed "hyperv_name" attribute is the real name of the hyperv object, this is also used in the id of the resource
+// remark that "hyperv_name" must be set in the resource's 'CustomizeDiff' using 'customizeDiffHypervName'
+// remark that arguments referring to other hyperv objects, f.i. "switch_name", use the real name of the hyperv object
+//     the config must refer to the "hyperv_name" of a managed object, not to its "name", f.i. 'switch_name = hyperv_vswitch.example.hyperv_name'
+
+func hypervNameSchema() *schema.Schema {
+    return &schema.Schema{
+        Type:     schema.TypeString,
+        Computed: true,
+    }
+}
+
+//------------------------------------------------------------------------------
+
+// customizeDiffHypervName sets "hyperv_name" from the "name" and the provider's "name_prefix"
+// when forceNew is true, a change of "hyperv_name" re-creates the hyperv object, f.i. when the "name_prefix" changes
+func customizeDiffHypervName(forceNew bool) schema.CustomizeDiffFunc {
+    return func(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
+        if !diff.NewValueKnown("name") {
+            return diff.SetNewComputed("hyperv_name")
+        }
+
+        hypervName := newHypervName(m, diff.Get("name").(string))
+
+        oldHypervName := diff.Get("hyperv_name").(string)
+        if oldHypervName == "" {
+            // terraform state from before the "name_prefix" was supported
+            oldName, _ := diff.GetChange("name")
+            oldHypervName = oldName.(string)
+        }
+
+        if strings.EqualFold(oldHypervName, hypervName) && diff.Get("hyperv_name").(string) != "" {
+            return nil
+        }
+
+        err := diff.SetNew("hyperv_name", hypervName)
+        if err != nil {
+            return err
+        }
+        if forceNew && diff.Id() != "" && !strings.EqualFold(oldHypervName, hypervName) {
+            return diff.ForceNew("hyperv_name")
+        }
+        return nil
+    }
+}
+
+// newHypervName returns the real name for a hyperv object, adding the provider's "name_prefix" to the name from the config
+func newHypervName(m interface{}, name string) string {
+    c := m.(*api.HypervClient)
+
+    return c.NamePrefix + name
+}
+
+// getHypervName returns the real name of the hyperv object from the terraform state
+func getHypervName(d *schema.ResourceData, m interface{}) string {
+    if hypervName := d.Get("hyperv_name").(string); hypervName != "" {
+        return hypervName
+    }
+    return newHypervName(m, d.Get("name").(string))   // terraform state from before the "name_prefix" was supported
+}
+
+// terraformName returns the name for the config, removing the provider's "name_prefix" from the real name of a hyperv object
+func terraformName(m interface{}, hypervName string) string {
+    c := m.(*api.HypervClient)
+
+    if len(hypervName) >= len(c.NamePrefix) && strings.EqualFold(hypervName[:len(c.NamePrefix)], c.NamePrefix) {
+        return hypervName[len(c.NamePrefix):]
+    }
+    return hypervName
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,57 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "testing"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+)
+
+//------------------------------------------------------------------------------
+
+// testClient returns a client for unit tests of the helper functions, it is never connected to a hyperv-server
+func testClient(namePrefix string) *api.HypervClient {
+    c := new(api.HypervClient)
+    c.Type       = "ssh"
+    c.Host       = "test-host"
+    c.NamePrefix = namePrefix
+    return c
+}
+
+func TestTerraformName(t *testing.T) {
+    tests := []struct {
+        namePrefix string
+        hypervName string
+        expected   string
+    }{
+        { "", "my-switch", "my-switch" },
+        { "ws1-", "ws1-my-switch", "my-switch" },
+        { "ws1-", "WS1-my-switch", "my-switch" },   // hyperv names are not case-sensitive
+        { "ws1-", "ws2-my-switch", "ws2-my-switch" },   // not managed with this prefix
+        { "ws1-", "ws1", "ws1" },
+        { "ws1-", "ws1-", "" },
+        { "ws1-", "", "" },
+    }
+
+    for _, test := range tests {
+        if actual := terraformName(testClient(test.namePrefix), test.hypervName); actual != test.expected {
+            t.Errorf("terraformName(%q) with 'name_prefix = %q': expected %q, got %q", test.hypervName, test.namePrefix, test.expected, actual)
+        }
+    }
+}
+
+func TestNewHypervName(t *testing.T) {
+    for _, namePrefix := range []string{ "", "ws1-" } {
+        m := testClient(namePrefix)
+        if actual := terraformName(m, newHypervName(m, "my-switch")); actual != "my-switch" {
+            t.Errorf("terraformName(newHypervName(%q)) with 'name_prefix = %q': expected %q, got %q", "my-switch", namePrefix, "my-switch", actual)
+        }
+    }
+}
+
+//------------------------------------------------------------------------------
@@ -7,18 +7,19 @@
 package hyperv
 
 import (
+    "context"
     "strings"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-    "github.com/hashicorp/terraform-plugin-sdk/terraform"
-    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
     "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
 )
 
 //------------------------------------------------------------------------------
 
-func Provider() terraform.ResourceProvider {
+func Provider() *schema.Provider {
     return &schema.Provider{
         Schema: map[string]*schema.Schema {
             "type": &schema.Schema{
@@ -68,23 +69,59 @@ func Provider() terraform.ResourceProvider {
                 Optional: true,
                 Default: false,
             },
+
+            // defaults for resources
+            "name_prefix": &schema.Schema{
+                Description: "The prefix added to the names of the managed hyperv objects, f.i. \"${terraform.workspace}-\"",
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+            },
+            "default_tags": &schema.Schema{
+                Description: "Tags added to all resources supporting tags",
+                Type:     schema.TypeList,
+                Optional: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "tags": &schema.Schema{
+                            Type:     schema.TypeMap,
+                            Optional: true,
+                            Elem:     &schema.Schema{ Type: schema.TypeString },
+                        },
+                    },
+                },
+            },
         },
 
         DataSourcesMap: map[string]*schema.Resource {
-            "hyperv_vswitch": dataSourceHypervVSwitch(),
+            "hyperv_net_adapters":       dataSourceHypervNetAdapters(),
+            "hyperv_vswitch":            dataSourceHypervVSwitch(),
+            "hyperv_vswitch_extensions": dataSourceHypervVSwitchExtensions(),
+            "hyperv_vswitches":          dataSourceHypervVSwitches(),
         },
 
         ResourcesMap: map[string]*schema.Resource{
-            "hyperv_vswitch": resourceHypervVSwitch(),
+            "hyperv_host_ip_address":       resourceHypervHostIPAddress(),
+            "hyperv_management_os_adapter": resourceHypervManagementOSAdapter(),
+            "hyperv_nat":                   resourceHypervNat(),
+            "hyperv_nat_static_mapping":    resourceHypervNatStaticMapping(),
+            "hyperv_vhd":                   resourceHypervVHD(),
+            "hyperv_vm":                    resourceHypervVM(),
+            "hyperv_vm_dvd_drive":          resourceHypervVMDvdDrive(),
+            "hyperv_vm_hard_disk_drive":    resourceHypervVMHardDiskDrive(),
+            "hyperv_vm_network_adapter":    resourceHypervVMNetworkAdapter(),
+            "hyperv_vswitch":               resourceHypervVSwitch(),
+            "hyperv_vswitch_extension":     resourceHypervVSwitchExtension(),
         },
 
-        ConfigureFunc: providerConfigure,
+        ConfigureContextFunc: providerConfigure,
     }
 }
 
 //------------------------------------------------------------------------------
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
     config := Config{
         Type:     strings.ToLower(d.Get("type").(string)),
 
@@ -96,7 +133,17 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
         Insecure: d.Get("insecure").(bool),
     }
 
-    return config.Client()
+    // defaults for resources
+    config.NamePrefix = d.Get("name_prefix").(string)
+    if default_tags := tfutil.GetResourceDataMap(d, "default_tags"); default_tags != nil {
+        config.DefaultTags = expandTags(default_tags["tags"].(map[string]interface{}))
+    }
+
+    client, err := config.Client()
+    if err != nil {
+        return nil, diag.FromErr(err)
+    }
+    return client, nil
 }
 
 //------------------------------------------------------------------------------
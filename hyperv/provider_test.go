@@ -0,0 +1,21 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "testing"
+)
+
+//------------------------------------------------------------------------------
+
+func TestProvider(t *testing.T) {
+    if err := Provider().InternalValidate(); err != nil {
+        t.Fatalf("err: %s", err)
+    }
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,267 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+    "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceHypervHostIPAddress () *schema.Resource {
+    return &schema.Resource{
+        CreateContext: resourceHypervHostIPAddressCreate,
+        ReadContext:   resourceHypervHostIPAddressRead,
+        UpdateContext: resourceHypervHostIPAddressUpdate,
+        DeleteContext: resourceHypervHostIPAddressDelete,
+
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceHypervHostIPAddressImport,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "interface_alias": &schema.Schema{                     // f.i. "vEthernet (Internal Switch)"
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "ip_address": &schema.Schema{
+                Type:     schema.TypeSet,
+                Required: true,
+                MinItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "address": &schema.Schema{                 // IPv4 or IPv6
+                            Type:     schema.TypeString,
+                            Required: true,
+
+                            ValidateFunc: validation.IsIPAddress,
+                        },
+                        "prefix_length": &schema.Schema{
+                            Type:     schema.TypeInt,
+                            Required: true,
+
+                            ValidateFunc: validation.IntBetween(0, 128),
+                        },
+                    },
+                },
+            },
+            "dns_server_addresses": &schema.Schema{                // defaults to the dns servers for the address families of "ip_address" on the host interface, not reset when removed from the config
+                Type:     schema.TypeList,
+                Optional: true,
+                Computed: true,
+                Elem:     &schema.Schema{
+                    Type: schema.TypeString,
+
+                    ValidateFunc: validation.IsIPAddress,
+                },
+            },
+
+            // computed
+            "interface_index": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+        },
+    }
+}
+
+func resourceHypervHostIPAddressCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id                 := fmt.Sprintf("//%s/host-interfaces/%s/ip-configuration", host, d.Get("interface_alias").(string))
+    interfaceAlias     := d.Get("interface_alias").(string)
+    ipAddresses        := expandHostIPAddresses(d.Get("ip_address").(*schema.Set))
+    dnsServerAddresses := expandStringList(d.Get("dns_server_addresses").([]interface{}))
+
+    log.Printf(`[INFO][terraform-provider-hyperv] creating hyperv_host_ip_address %q
+                    [INFO][terraform-provider-hyperv]     interface_alias:      %#v
+                    [INFO][terraform-provider-hyperv]     ip_address:           %#v
+                    [INFO][terraform-provider-hyperv]     dns_server_addresses: %#v
+`   , id, interfaceAlias, ipAddresses, dnsServerAddresses)
+
+    // create host ip configuration
+    hipcProperties := new(api.HostIPConfiguration)
+    hipcProperties.InterfaceAlias     = interfaceAlias
+    hipcProperties.IPAddresses        = ipAddresses
+    hipcProperties.DnsServerAddresses = dnsServerAddresses
+
+    err := c.CreateHostIPConfiguration(hipcProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_host_ip_address %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] created hyperv_host_ip_address %q\n", id)
+    return resourceHypervHostIPAddressRead(ctx, d, m)
+}
+
+func resourceHypervHostIPAddressRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id             := d.Id()
+    interfaceAlias := d.Get("interface_alias").(string)
+
+    log.Printf("[INFO][terraform-provider-hyperv] reading hyperv_host_ip_address %q\n", id)
+
+    // read host ip configuration
+    hipc := new(api.HostIPConfiguration)
+    hipc.InterfaceAlias = interfaceAlias
+
+    hostIPConfiguration, err := c.ReadHostIPConfiguration(hipc)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-hyperv] cannot read hyperv_host_ip_address %q\n", id)
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_host_ip_address %q from terraform state\n", id)
+        return nil   // don't return an error to allow terraform refresh to update state
+    }
+
+    // set properties, any manual changes to the addresses or to the dns servers for their address families show up as a diff
+    d.Set("interface_alias", hostIPConfiguration.InterfaceAlias)
+    d.Set("ip_address", flattenHostIPAddresses(hostIPConfiguration.IPAddresses))
+    d.Set("dns_server_addresses", hostIPConfiguration.DnsServerAddresses)
+    d.Set("interface_index", hostIPConfiguration.InterfaceIndex)
+
+    log.Printf("[INFO][terraform-provider-hyperv] read hyperv_host_ip_address %q\n", id)
+    return nil
+}
+
+func resourceHypervHostIPAddressUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id                 := d.Id()
+    interfaceAlias     := d.Get("interface_alias").(string)
+    ipAddresses        := expandHostIPAddresses(d.Get("ip_address").(*schema.Set))
+    dnsServerAddresses := expandStringList(d.Get("dns_server_addresses").([]interface{}))
+
+    log.Printf(`[INFO][terraform-provider-hyperv] updating hyperv_host_ip_address %q
+                    [INFO][terraform-provider-hyperv]     interface_alias:      %#v
+                    [INFO][terraform-provider-hyperv]     ip_address:           %#v
+                    [INFO][terraform-provider-hyperv]     dns_server_addresses: %#v
+`   , id, interfaceAlias, ipAddresses, dnsServerAddresses)
+
+    // update host ip configuration
+    hipc := new(api.HostIPConfiguration)
+    hipc.InterfaceAlias = interfaceAlias
+
+    hipcProperties := new(api.HostIPConfiguration)
+    hipcProperties.IPAddresses        = ipAddresses
+    hipcProperties.DnsServerAddresses = dnsServerAddresses
+
+    err := c.UpdateHostIPConfiguration(hipc, hipcProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_host_ip_address %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv] updated hyperv_host_ip_address %q\n", id)
+    return resourceHypervHostIPAddressRead(ctx, d, m)
+}
+
+func resourceHypervHostIPAddressDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id             := d.Id()
+    interfaceAlias := d.Get("interface_alias").(string)
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleting hyperv_host_ip_address %q\n", id)
+
+    // delete host ip configuration
+    hipc := new(api.HostIPConfiguration)
+    hipc.InterfaceAlias = interfaceAlias
+
+    err := c.DeleteHostIPConfiguration(hipc)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot delete hyperv_host_ip_address %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_host_ip_address %q\n", id)
+    return nil
+}
+
+func resourceHypervHostIPAddressImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    importID := d.Id()   // importID is the alias of the host interface
+    id       := fmt.Sprintf("//%s/host-interfaces/%s/ip-configuration", host, importID)
+
+    log.Printf("[INFO][terraform-provider-hyperv] importing hyperv_host_ip_address %q\n", id)
+
+    // set properties
+    d.Set("interface_alias", importID)
+
+    // set id
+    d.SetId(id)
+
+    return []*schema.ResourceData{ d }, nil
+}
+
+//------------------------------------------------------------------------------
+
+func expandHostIPAddresses(s *schema.Set) []api.HostIPAddress {
+    ipAddresses := make([]api.HostIPAddress, 0, s.Len())
+    for _, v := range s.List() {
+        m := v.(map[string]interface{})
+        ipAddresses = append(ipAddresses, api.HostIPAddress{
+            IPAddress:    m["address"].(string),
+            PrefixLength: m["prefix_length"].(int),
+        })
+    }
+    return ipAddresses
+}
+
+func flattenHostIPAddresses(ipAddresses []api.HostIPAddress) []map[string]interface{} {
+    l := make([]map[string]interface{}, 0, len(ipAddresses))
+    for _, ipAddress := range ipAddresses {
+        l = append(l, map[string]interface{}{
+            "address":       ipAddress.IPAddress,
+            "prefix_length": ipAddress.PrefixLength,
+        })
+    }
+    return l
+}
+
+func expandStringList(l []interface{}) []string {
+    s := make([]string, 0, len(l))
+    for _, v := range l {
+        s = append(s, v.(string))
+    }
+    return s
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,290 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+    "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceHypervManagementOSAdapter () *schema.Resource {
+    return &schema.Resource{
+        CreateContext: resourceHypervManagementOSAdapterCreate,
+        ReadContext:   resourceHypervManagementOSAdapterRead,
+        UpdateContext: resourceHypervManagementOSAdapterUpdate,
+        DeleteContext: resourceHypervManagementOSAdapterDelete,
+
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceHypervManagementOSAdapterImport,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "switch_name": &schema.Schema{                         // the real name of the vswitch, f.i. 'hyperv_vswitch.example.hyperv_name'
+                Type:     schema.TypeString,
+                Required: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "vlan_id": &schema.Schema{                             // 0 is untagged
+                Type:     schema.TypeInt,
+                Optional: true,
+                Default:  0,
+
+                ValidateFunc: validation.IntBetween(0, 4094),
+            },
+            "minimum_bandwidth_weight": &schema.Schema{            // 0 is not configured, requires a vswitch with weight-based bandwidth reservation
+                Type:     schema.TypeInt,
+                Optional: true,
+                Default:  0,
+
+                ValidateFunc: validation.IntBetween(0, 100),
+            },
+            "mac_address": &schema.Schema{                         // defaults to a dynamic mac-address
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressMacAddress(),
+            },
+
+            // computed
+            "hyperv_name": hypervNameSchema(),
+            "interface_index": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+            "interface_alias": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+        },
+
+        CustomizeDiff: customizeDiffHypervName(false),   // the adapter is renamed when the "name_prefix" changes
+    }
+}
+
+func resourceHypervManagementOSAdapterCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    switchName             := d.Get("switch_name").(string)
+    name                   := d.Get("name").(string)
+    hypervName             := newHypervName(m, name)
+    id                     := fmt.Sprintf("//%s/management-os-adapters/%s", host, hypervName)
+    vlanId                 := d.Get("vlan_id").(int)
+    minimumBandwidthWeight := d.Get("minimum_bandwidth_weight").(int)
+    macAddress             := d.Get("mac_address").(string)
+
+    macAddress_msg := d.Get("mac_address")
+    if macAddress == "" { macAddress_msg = "(computed)" }
+    log.Printf(`[INFO][terraform-provider-hyperv] creating hyperv_management_os_adapter %q
+                    [INFO][terraform-provider-hyperv]     switch_name:              %#v
+                    [INFO][terraform-provider-hyperv]     name:                     %#v
+                    [INFO][terraform-provider-hyperv]     hyperv_name:              %#v
+                    [INFO][terraform-provider-hyperv]     vlan_id:                  %#v
+                    [INFO][terraform-provider-hyperv]     minimum_bandwidth_weight: %#v
+                    [INFO][terraform-provider-hyperv]     mac_address:              %#v
+`   , id, switchName, name, hypervName, vlanId, minimumBandwidthWeight, macAddress_msg)
+
+    // create management-os adapter
+    moaProperties := new(api.ManagementOSAdapter)
+    moaProperties.SwitchName             = switchName
+    moaProperties.Name                   = hypervName
+    moaProperties.VlanId                 = vlanId
+    moaProperties.MinimumBandwidthWeight = minimumBandwidthWeight
+    moaProperties.MacAddress             = macAddress
+
+    err := c.CreateManagementOSAdapter(moaProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_management_os_adapter %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] created hyperv_management_os_adapter %q\n", id)
+    return resourceHypervManagementOSAdapterRead(ctx, d, m)
+}
+
+func resourceHypervManagementOSAdapterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id   := d.Id()
+    name := getHypervName(d, m)
+
+    log.Printf("[INFO][terraform-provider-hyperv] reading hyperv_management_os_adapter %q\n", id)
+
+    // read management-os adapter
+    moa := new(api.ManagementOSAdapter)
+    moa.Name = name
+
+    managementOSAdapter, err := c.ReadManagementOSAdapter(moa)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-hyperv] cannot read hyperv_management_os_adapter %q\n", id)
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_management_os_adapter %q from terraform state\n", id)
+        return nil   // don't return an error to allow terraform refresh to update state
+    }
+
+    // set properties
+    d.Set("switch_name", managementOSAdapter.SwitchName)
+    d.Set("name", terraformName(m, managementOSAdapter.Name))
+    d.Set("hyperv_name", managementOSAdapter.Name)
+    d.Set("vlan_id", managementOSAdapter.VlanId)
+    d.Set("minimum_bandwidth_weight", managementOSAdapter.MinimumBandwidthWeight)
+    d.Set("mac_address", managementOSAdapter.MacAddress)
+    d.Set("interface_index", managementOSAdapter.InterfaceIndex)
+    d.Set("interface_alias", managementOSAdapter.InterfaceAlias)
+
+    log.Printf("[INFO][terraform-provider-hyperv] read hyperv_management_os_adapter %q\n", id)
+    return nil
+}
+
+func resourceHypervManagementOSAdapterUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id                     := d.Id()
+    oldHypervName, _       := d.GetChange("hyperv_name")
+    switchName             := d.Get("switch_name").(string)
+    name                   := d.Get("name").(string)
+    hypervName             := newHypervName(m, name)
+    vlanId                 := d.Get("vlan_id").(int)
+    minimumBandwidthWeight := d.Get("minimum_bandwidth_weight").(int)
+    macAddress             := d.Get("mac_address").(string)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] updating hyperv_management_os_adapter %q
+                    [INFO][terraform-provider-hyperv]     switch_name:              %#v
+                    [INFO][terraform-provider-hyperv]     name:                     %#v
+                    [INFO][terraform-provider-hyperv]     hyperv_name:              %#v
+                    [INFO][terraform-provider-hyperv]     vlan_id:                  %#v
+                    [INFO][terraform-provider-hyperv]     minimum_bandwidth_weight: %#v
+                    [INFO][terraform-provider-hyperv]     mac_address:              %#v
+`   , id, switchName, name, hypervName, vlanId, minimumBandwidthWeight, macAddress)
+
+    // update management-os adapter
+    moa := new(api.ManagementOSAdapter)
+    moa.Name = oldHypervName.(string)
+    if moa.Name == "" {
+        oldName, _ := d.GetChange("name")   // terraform state from before the "name_prefix" was supported
+        moa.Name = oldName.(string)
+    }
+
+    moaProperties := new(api.ManagementOSAdapter)
+    moaProperties.SwitchName             = switchName
+    moaProperties.Name                   = hypervName
+    moaProperties.VlanId                 = vlanId
+    moaProperties.MinimumBandwidthWeight = minimumBandwidthWeight
+    if d.HasChange("mac_address") {
+        moaProperties.MacAddress = macAddress
+    }
+
+    err := c.UpdateManagementOSAdapter(moa, moaProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_management_os_adapter %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id, the adapter may have been renamed
+    id = fmt.Sprintf("//%s/management-os-adapters/%s", host, hypervName)
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] updated hyperv_management_os_adapter %q\n", id)
+    return resourceHypervManagementOSAdapterRead(ctx, d, m)
+}
+
+func resourceHypervManagementOSAdapterDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id   := d.Id()
+    name := getHypervName(d, m)
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleting hyperv_management_os_adapter %q\n", id)
+
+    // delete management-os adapter
+    moa := new(api.ManagementOSAdapter)
+    moa.Name = name
+
+    err := c.DeleteManagementOSAdapter(moa)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot delete hyperv_management_os_adapter %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_management_os_adapter %q\n", id)
+    return nil
+}
+
+func resourceHypervManagementOSAdapterImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    // importID is the id "//<host>/management-os-adapters/<name>", or the real name of the management-os adapter including the provider's "name_prefix"
+    importID, err := parseImportID(c, d.Id(), "management-os-adapters")
+    if err != nil {
+        return nil, err
+    }
+
+    // verify the management-os adapter exists
+    moa := new(api.ManagementOSAdapter)
+    moa.Name = importID
+
+    _, err = c.ReadManagementOSAdapter(moa)
+    if err != nil {
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervManagementOSAdapterImport()] cannot find management-os adapter %q", importID)
+    }
+
+    id := fmt.Sprintf("//%s/management-os-adapters/%s", host, importID)
+
+    log.Printf("[INFO][terraform-provider-hyperv] importing hyperv_management_os_adapter %q\n", id)
+
+    // set properties
+    d.Set("name", terraformName(m, importID))
+    d.Set("hyperv_name", importID)
+
+    // set id
+    d.SetId(id)
+
+    return []*schema.ResourceData{ d }, nil
+}
+
+//------------------------------------------------------------------------------
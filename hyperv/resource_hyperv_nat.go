@@ -0,0 +1,194 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+    "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceHypervNat () *schema.Resource {
+    return &schema.Resource{
+        CreateContext: resourceHypervNatCreate,
+        ReadContext:   resourceHypervNatRead,
+        DeleteContext: resourceHypervNatDelete,
+
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceHypervNatImport,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "internal_ip_interface_address_prefix": &schema.Schema{   // f.i. "192.168.100.0/24"
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.IsCIDRNetwork(0, 128),
+            },
+
+            // computed
+            "hyperv_name": hypervNameSchema(),
+            "active": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+        },
+
+        CustomizeDiff: customizeDiffHypervName(true),
+    }
+}
+
+func resourceHypervNatCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    name                             := d.Get("name").(string)
+    hypervName                       := newHypervName(m, name)
+    id                               := fmt.Sprintf("//%s/nats/%s", host, hypervName)
+    internalIPInterfaceAddressPrefix := d.Get("internal_ip_interface_address_prefix").(string)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] creating hyperv_nat %q
+                    [INFO][terraform-provider-hyperv]     name:                                 %#v
+                    [INFO][terraform-provider-hyperv]     hyperv_name:                          %#v
+                    [INFO][terraform-provider-hyperv]     internal_ip_interface_address_prefix: %#v
+`   , id, name, hypervName, internalIPInterfaceAddressPrefix)
+
+    // create nat
+    natProperties := new(api.Nat)
+    natProperties.Name                             = hypervName
+    natProperties.InternalIPInterfaceAddressPrefix = internalIPInterfaceAddressPrefix
+
+    err := c.CreateNat(natProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_nat %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] created hyperv_nat %q\n", id)
+    return resourceHypervNatRead(ctx, d, m)
+}
+
+func resourceHypervNatRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id   := d.Id()
+    name := getHypervName(d, m)
+
+    log.Printf("[INFO][terraform-provider-hyperv] reading hyperv_nat %q\n", id)
+
+    // read nat
+    nat := new(api.Nat)
+    nat.Name = name
+
+    netnat, err := c.ReadNat(nat)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-hyperv] cannot read hyperv_nat %q\n", id)
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_nat %q from terraform state\n", id)
+        return nil   // don't return an error to allow terraform refresh to update state
+    }
+
+    // set properties
+    d.Set("name", terraformName(m, netnat.Name))
+    d.Set("hyperv_name", netnat.Name)
+    d.Set("internal_ip_interface_address_prefix", netnat.InternalIPInterfaceAddressPrefix)
+    d.Set("active", netnat.Active)
+
+    log.Printf("[INFO][terraform-provider-hyperv] read hyperv_nat %q\n", id)
+    return nil
+}
+
+func resourceHypervNatDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id   := d.Id()
+    name := getHypervName(d, m)
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleting hyperv_nat %q\n", id)
+
+    // delete nat
+    nat := new(api.Nat)
+    nat.Name = name
+
+    err := c.DeleteNat(nat)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot delete hyperv_nat %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_nat %q\n", id)
+    return nil
+}
+
+func resourceHypervNatImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    // importID is the id "//<host>/nats/<name>", or the real name of the nat including the provider's "name_prefix"
+    importID, err := parseImportID(c, d.Id(), "nats")
+    if err != nil {
+        return nil, err
+    }
+
+    // verify the nat exists
+    n := new(api.Nat)
+    n.Name = importID
+
+    _, err = c.ReadNat(n)
+    if err != nil {
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervNatImport()] cannot find nat %q", importID)
+    }
+
+    id := fmt.Sprintf("//%s/nats/%s", host, importID)
+
+    log.Printf("[INFO][terraform-provider-hyperv] importing hyperv_nat %q\n", id)
+
+    // set properties
+    d.Set("name", terraformName(m, importID))
+    d.Set("hyperv_name", importID)
+
+    // set id
+    d.SetId(id)
+
+    return []*schema.ResourceData{ d }, nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,264 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "strconv"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+    "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceHypervNatStaticMapping () *schema.Resource {
+    return &schema.Resource{
+        CreateContext: resourceHypervNatStaticMappingCreate,
+        ReadContext:   resourceHypervNatStaticMappingRead,
+        DeleteContext: resourceHypervNatStaticMappingDelete,
+
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceHypervNatStaticMappingImport,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "nat_name": &schema.Schema{                            // the real name of the nat, f.i. 'hyperv_nat.example.hyperv_name'
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "protocol": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                ValidateFunc:     validation.StringInSlice([]string{ "tcp", "udp" }, true),
+                StateFunc:        tfutil.StateToLower(),
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "external_ip_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "0.0.0.0",
+                ForceNew: true,
+
+                ValidateFunc: validation.IsIPAddress,
+            },
+            "external_port": &schema.Schema{
+                Type:     schema.TypeInt,
+                Required: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.IntBetween(1, 65535),
+            },
+            "internal_ip_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.IsIPAddress,
+            },
+            "internal_port": &schema.Schema{
+                Type:     schema.TypeInt,
+                Required: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.IntBetween(1, 65535),
+            },
+
+            // computed
+            "static_mapping_id": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+            "active": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+        },
+    }
+}
+
+func resourceHypervNatStaticMappingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    natName           := d.Get("nat_name").(string)
+    protocol          := strings.ToLower(d.Get("protocol").(string))
+    externalIPAddress := d.Get("external_ip_address").(string)
+    externalPort      := d.Get("external_port").(int)
+    internalIPAddress := d.Get("internal_ip_address").(string)
+    internalPort      := d.Get("internal_port").(int)
+    id                := fmt.Sprintf("//%s/nats/%s/static-mappings/%s/%s:%d", host, natName, protocol, externalIPAddress, externalPort)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] creating hyperv_nat_static_mapping %q
+                    [INFO][terraform-provider-hyperv]     nat_name:            %#v
+                    [INFO][terraform-provider-hyperv]     protocol:            %#v
+                    [INFO][terraform-provider-hyperv]     external_ip_address: %#v
+                    [INFO][terraform-provider-hyperv]     external_port:       %#v
+                    [INFO][terraform-provider-hyperv]     internal_ip_address: %#v
+                    [INFO][terraform-provider-hyperv]     internal_port:       %#v
+`   , id, natName, protocol, externalIPAddress, externalPort, internalIPAddress, internalPort)
+
+    // create nat static mapping
+    nsmProperties := new(api.NatStaticMapping)
+    nsmProperties.NatName           = natName
+    nsmProperties.Protocol          = protocol
+    nsmProperties.ExternalIPAddress = externalIPAddress
+    nsmProperties.ExternalPort      = externalPort
+    nsmProperties.InternalIPAddress = internalIPAddress
+    nsmProperties.InternalPort      = internalPort
+
+    err := c.CreateNatStaticMapping(nsmProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_nat_static_mapping %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] created hyperv_nat_static_mapping %q\n", id)
+    return resourceHypervNatStaticMappingRead(ctx, d, m)
+}
+
+func resourceHypervNatStaticMappingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-hyperv] reading hyperv_nat_static_mapping %q\n", id)
+
+    // read nat static mapping
+    nsm := new(api.NatStaticMapping)
+    nsm.NatName           = d.Get("nat_name").(string)
+    nsm.Protocol          = strings.ToLower(d.Get("protocol").(string))
+    nsm.ExternalIPAddress = d.Get("external_ip_address").(string)
+    nsm.ExternalPort      = d.Get("external_port").(int)
+
+    natStaticMapping, err := c.ReadNatStaticMapping(nsm)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-hyperv] cannot read hyperv_nat_static_mapping %q\n", id)
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_nat_static_mapping %q from terraform state\n", id)
+        return nil   // don't return an error to allow terraform refresh to update state
+    }
+
+    // set properties
+    d.Set("nat_name", natStaticMapping.NatName)
+    d.Set("protocol", natStaticMapping.Protocol)
+    d.Set("external_ip_address", natStaticMapping.ExternalIPAddress)
+    d.Set("external_port", natStaticMapping.ExternalPort)
+    d.Set("internal_ip_address", natStaticMapping.InternalIPAddress)
+    d.Set("internal_port", natStaticMapping.InternalPort)
+    d.Set("static_mapping_id", natStaticMapping.StaticMappingID)
+    d.Set("active", natStaticMapping.Active)
+
+    log.Printf("[INFO][terraform-provider-hyperv] read hyperv_nat_static_mapping %q\n", id)
+    return nil
+}
+
+func resourceHypervNatStaticMappingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleting hyperv_nat_static_mapping %q\n", id)
+
+    // delete nat static mapping
+    nsm := new(api.NatStaticMapping)
+    nsm.NatName           = d.Get("nat_name").(string)
+    nsm.Protocol          = strings.ToLower(d.Get("protocol").(string))
+    nsm.ExternalIPAddress = d.Get("external_ip_address").(string)
+    nsm.ExternalPort      = d.Get("external_port").(int)
+
+    err := c.DeleteNatStaticMapping(nsm)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot delete hyperv_nat_static_mapping %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_nat_static_mapping %q\n", id)
+    return nil
+}
+
+func resourceHypervNatStaticMappingImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    // importID is the id "//<host>/nats/<nat_name>/static-mappings/<protocol>/<external_ip_address>:<external_port>", or "<nat_name>/<protocol>/<external_ip_address>:<external_port>"
+    importID, err := parseImportPath(c, d.Id(), "nats")
+    if err != nil {
+        return nil, err
+    }
+    importID = strings.Replace(importID, "/static-mappings/", "/", 1)
+
+    parts := strings.Split(importID, "/")
+    if len(parts) != 3 || strings.LastIndex(parts[2], ":") < 0 {
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervNatStaticMappingImport()] invalid import ID %q, expected \"<nat_name>/<protocol>/<external_ip_address>:<external_port>\"", importID)
+    }
+    i := strings.LastIndex(parts[2], ":")
+    externalPort, err := strconv.Atoi(parts[2][i+1:])
+    if err != nil {
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervNatStaticMappingImport()] invalid external port in import ID %q", importID)
+    }
+    natName           := parts[0]
+    protocol          := strings.ToLower(parts[1])
+    externalIPAddress := parts[2][:i]
+    id                := fmt.Sprintf("//%s/nats/%s/static-mappings/%s/%s:%d", host, natName, protocol, externalIPAddress, externalPort)
+
+    // verify the nat static mapping exists
+    nsm := new(api.NatStaticMapping)
+    nsm.NatName           = natName
+    nsm.Protocol          = protocol
+    nsm.ExternalIPAddress = externalIPAddress
+    nsm.ExternalPort      = externalPort
+
+    _, err = c.ReadNatStaticMapping(nsm)
+    if err != nil {
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervNatStaticMappingImport()] cannot find nat static mapping %q", importID)
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv] importing hyperv_nat_static_mapping %q\n", id)
+
+    // set properties
+    d.Set("nat_name", natName)
+    d.Set("protocol", protocol)
+    d.Set("external_ip_address", externalIPAddress)
+    d.Set("external_port", externalPort)
+
+    // set id
+    d.SetId(id)
+
+    return []*schema.ResourceData{ d }, nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,417 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+    "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceHypervVHD () *schema.Resource {
+    return &schema.Resource{
+        CreateContext: resourceHypervVHDCreate,
+        ReadContext:   resourceHypervVHDRead,
+        UpdateContext: resourceHypervVHDUpdate,
+        DeleteContext: resourceHypervVHDDelete,
+
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceHypervVHDImport,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "path": &schema.Schema{                                // the path of the file on the hyperv-server, the extension must match the format - the vhd is converted when only the extension changes
+                Type:     schema.TypeString,
+                Required: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "format": &schema.Schema{                              // defaults to the extension of the path, the vhd is converted when the format changes
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc:     validation.StringInSlice([]string{ "vhd", "vhdx" }, true),
+                StateFunc:        tfutil.StateToLower(),
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "type": &schema.Schema{                                // the vhd is converted when the type changes, except to "differencing"
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "dynamic",
+
+                ValidateFunc:     validation.StringInSlice([]string{ "fixed", "dynamic", "differencing" }, true),
+                StateFunc:        tfutil.StateToLower(),
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "size": &schema.Schema{                                // required, except for a differencing disk, where it defaults to the size of the parent - the vhd is resized when the size changes
+                Type:     schema.TypeInt,
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: validation.IntDivisibleBy(512),
+            },
+            "allow_shrink": &schema.Schema{                        // the size of the vhd cannot be reduced, unless allowed
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+            },
+            "block_size": &schema.Schema{                          // defaults to the default block size of the hyperv-server
+                Type:     schema.TypeInt,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.IntAtLeast(1),
+            },
+            "logical_sector_size": &schema.Schema{                 // defaults to the default logical sector size of the hyperv-server
+                Type:     schema.TypeInt,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.IntInSlice([]int{ 512, 4096 }),
+            },
+            "physical_sector_size": &schema.Schema{                // defaults to the default physical sector size of the hyperv-server
+                Type:     schema.TypeInt,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.IntInSlice([]int{ 512, 4096 }),
+            },
+            "parent_path": &schema.Schema{                         // required for a differencing disk, the vhd is merged with its parent when converting to another type
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+
+            // computed
+            "file_size": &schema.Schema{                           // the size of the file on the hyperv-server
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+            "fragmentation_percentage": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+            "attached": &schema.Schema{                            // attached to a running vm or mounted on the hyperv-server
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+        },
+
+        CustomizeDiff: customdiff.All(
+            validateVHDType,
+            validateVHDShrink,
+            customizeDiffVHDFormat,
+            customdiff.ForceNewIfChange("path", func(ctx context.Context, old, new, m interface{}) bool {
+                return !strings.EqualFold(vhdPathWithoutExtension(old.(string)), vhdPathWithoutExtension(new.(string)))   // the vhd is converted when only the extension changes
+            }),
+            customdiff.ForceNewIfChange("type", func(ctx context.Context, old, new, m interface{}) bool {
+                return strings.EqualFold(new.(string), "differencing")   // cannot convert to a differencing disk
+            }),
+            customdiff.ForceNewIfChange("parent_path", func(ctx context.Context, old, new, m interface{}) bool {
+                return new.(string) != ""   // cannot change the parent of a differencing disk
+            }),
+        ),
+    }
+}
+
+func validateVHDType(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
+    vhd_type := strings.ToLower(diff.Get("type").(string))
+
+    // "parent_path"
+    if vhd_type == "differencing" {
+        if diff.Get("parent_path").(string) == "" && diff.NewValueKnown("parent_path") {
+            return fmt.Errorf("\"parent_path\": required for 'type = %q'", vhd_type)
+        }
+    } else {
+        if diff.Get("parent_path").(string) != "" {
+            return fmt.Errorf("\"parent_path\": conflicts with 'type = %q'", vhd_type)
+        }
+    }
+
+    // "size"
+    if vhd_type != "differencing" && diff.Id() == "" {
+        if _, ok := diff.GetOk("size"); !ok && diff.NewValueKnown("size") {
+            return fmt.Errorf("\"size\": required for 'type = %q'", vhd_type)
+        }
+    }
+    return nil
+}
+
+func customizeDiffVHDFormat(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
+    // the format defaults to the extension of the path
+    if diff.GetRawConfig().GetAttr("format").IsNull() {
+        if diff.Id() != "" && diff.HasChange("path") {
+            return diff.SetNewComputed("format")
+        }
+        return nil
+    }
+
+    path := diff.Get("path").(string)
+    format := strings.ToLower(diff.Get("format").(string))
+    if format != "" && path != "" && !strings.HasSuffix(strings.ToLower(path), "." + format) {
+        return fmt.Errorf("\"format\": the extension of path %q doesn't match 'format = %q'", path, format)
+    }
+    return nil
+}
+
+func validateVHDShrink(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
+    if diff.Id() == "" || !diff.HasChange("size") || diff.Get("allow_shrink").(bool) {
+        return nil
+    }
+
+    old, new := diff.GetChange("size")
+    if new.(int) != 0 && new.(int) < old.(int) {
+        return fmt.Errorf("\"size\": cannot shrink the vhd from %d to %d bytes - use 'allow_shrink = true' to allow this", old.(int), new.(int))
+    }
+    return nil
+}
+
+func resourceHypervVHDCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    path               := d.Get("path").(string)
+    id                 := fmt.Sprintf("//%s/vhds/%s", host, path)
+    format             := strings.ToLower(d.Get("format").(string))
+    vhdType            := strings.ToLower(d.Get("type").(string))
+    size               := d.Get("size").(int)
+    blockSize          := d.Get("block_size").(int)
+    logicalSectorSize  := d.Get("logical_sector_size").(int)
+    physicalSectorSize := d.Get("physical_sector_size").(int)
+    parentPath         := d.Get("parent_path").(string)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] creating hyperv_vhd %q
+                    [INFO][terraform-provider-hyperv]     path:                 %#v
+                    [INFO][terraform-provider-hyperv]     format:               %#v
+                    [INFO][terraform-provider-hyperv]     type:                 %#v
+                    [INFO][terraform-provider-hyperv]     size:                 %#v
+                    [INFO][terraform-provider-hyperv]     block_size:           %#v
+                    [INFO][terraform-provider-hyperv]     logical_sector_size:  %#v
+                    [INFO][terraform-provider-hyperv]     physical_sector_size: %#v
+                    [INFO][terraform-provider-hyperv]     parent_path:          %#v
+`   , id, path, format, vhdType, size, blockSize, logicalSectorSize, physicalSectorSize, parentPath)
+
+    // create vhd
+    vhdProperties := new(api.VHD)
+    vhdProperties.Path               = path
+    vhdProperties.Format             = format
+    vhdProperties.Type               = vhdType
+    vhdProperties.Size               = int64(size)
+    vhdProperties.BlockSize          = int64(blockSize)
+    vhdProperties.LogicalSectorSize  = logicalSectorSize
+    vhdProperties.PhysicalSectorSize = physicalSectorSize
+    vhdProperties.ParentPath         = parentPath
+
+    err := c.CreateVHD(vhdProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_vhd %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] created hyperv_vhd %q\n", id)
+    return resourceHypervVHDRead(ctx, d, m)
+}
+
+func resourceHypervVHDRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-hyperv] reading hyperv_vhd %q\n", id)
+
+    // read vhd
+    v := new(api.VHD)
+    v.Path = d.Get("path").(string)
+
+    vhd, err := c.ReadVHD(v)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-hyperv] cannot read hyperv_vhd %q\n", id)
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vhd %q from terraform state\n", id)
+        return nil   // don't return an error to allow terraform refresh to update state
+    }
+
+    // set properties
+    d.Set("path", vhd.Path)
+    d.Set("format", vhd.Format)
+    d.Set("type", vhd.Type)
+    d.Set("size", vhd.Size)
+    d.Set("block_size", vhd.BlockSize)
+    d.Set("logical_sector_size", vhd.LogicalSectorSize)
+    d.Set("physical_sector_size", vhd.PhysicalSectorSize)
+    d.Set("parent_path", vhd.ParentPath)
+    d.Set("file_size", vhd.FileSize)
+    d.Set("fragmentation_percentage", vhd.FragmentationPercentage)
+    d.Set("attached", vhd.Attached)
+
+    log.Printf("[INFO][terraform-provider-hyperv] read hyperv_vhd %q\n", id)
+    return nil
+}
+
+func resourceHypervVHDUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id          := d.Id()
+    oldPath, _  := d.GetChange("path")
+    path        := d.Get("path").(string)
+    format      := strings.ToLower(d.Get("format").(string))
+    vhdType     := strings.ToLower(d.Get("type").(string))
+    size        := d.Get("size").(int)
+    allowShrink := d.Get("allow_shrink").(bool)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] updating hyperv_vhd %q
+                    [INFO][terraform-provider-hyperv]     path:                 %#v
+                    [INFO][terraform-provider-hyperv]     format:               %#v
+                    [INFO][terraform-provider-hyperv]     type:                 %#v
+                    [INFO][terraform-provider-hyperv]     size:                 %#v
+                    [INFO][terraform-provider-hyperv]     allow_shrink:         %#v
+`   , id, path, format, vhdType, size, allowShrink)
+
+    // changes in 'allow_shrink' only, must not trigger an update in infrastructure
+    if !d.HasChange("path") &&
+       !d.HasChange("format") &&
+       !d.HasChange("type") &&
+       !d.HasChange("size") {
+        log.Printf("[INFO][terraform-provider-hyperv] updated hyperv_vhd %q in terraform state, no change in infrastructure\n", id)
+        return resourceHypervVHDRead(ctx, d, m)
+    }
+
+    // update vhd
+    v := new(api.VHD)
+    v.Path = oldPath.(string)
+
+    vhdProperties := new(api.VHD)
+    vhdProperties.Path   = path
+    vhdProperties.Format = format
+    vhdProperties.Type   = vhdType
+    if d.HasChange("size") {
+        vhdProperties.Size = int64(size)
+    }
+
+    err := c.UpdateVHD(v, vhdProperties, allowShrink)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_vhd %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id, the vhd may have been converted to another path
+    id = fmt.Sprintf("//%s/vhds/%s", host, path)
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] updated hyperv_vhd %q\n", id)
+    return resourceHypervVHDRead(ctx, d, m)
+}
+
+func resourceHypervVHDDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleting hyperv_vhd %q\n", id)
+
+    // delete vhd
+    v := new(api.VHD)
+    v.Path = d.Get("path").(string)
+
+    err := c.DeleteVHD(v)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot delete hyperv_vhd %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vhd %q\n", id)
+    return nil
+}
+
+func resourceHypervVHDImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    // importID is the id "//<host>/vhds/<path>", or the path of the vhd on the hyperv-server
+    importID, err := parseImportPath(c, d.Id(), "vhds")
+    if err != nil {
+        return nil, err
+    }
+
+    // verify the vhd exists, and get its real path
+    v := new(api.VHD)
+    v.Path = importID
+
+    vhd, err := c.ReadVHD(v)
+    if err != nil {
+        if !strings.Contains(err.Error(), "cannot find vhd") {
+            return nil, err
+        }
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVHDImport()] cannot find vhd %q", importID)
+    }
+    importID = vhd.Path
+
+    id := fmt.Sprintf("//%s/vhds/%s", host, importID)
+
+    log.Printf("[INFO][terraform-provider-hyperv] importing hyperv_vhd %q\n", id)
+
+    // set properties
+    d.Set("path", importID)
+
+    // set id
+    d.SetId(id)
+
+    return []*schema.ResourceData{ d }, nil
+}
+
+//------------------------------------------------------------------------------
+
+// vhdPathWithoutExtension returns the path of a vhd without the ".vhd" or ".vhdx" extension
+//     remark that "path/filepath" cannot be used, the path is a path on the hyperv-server
+func vhdPathWithoutExtension(path string) string {
+    i := strings.LastIndexAny(path, `.\/`)
+    if i >= 0 && path[i] == '.' {
+        return path[:i]
+    }
+    return path
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,36 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "testing"
+)
+
+//------------------------------------------------------------------------------
+
+func TestVHDPathWithoutExtension(t *testing.T) {
+    tests := []struct {
+        path     string
+        expected string
+    }{
+        { `D:\VHDs\web.vhdx`, `D:\VHDs\web` },
+        { `D:\VHDs\web.vhd`, `D:\VHDs\web` },
+        { `D:\VHDs\web`, `D:\VHDs\web` },
+        { `D:\VHDs.old\web`, `D:\VHDs.old\web` },   // not an extension of the file
+        { `D:\VHDs.old\web.vhdx`, `D:\VHDs.old\web` },
+        { `D:/VHDs.old/web`, `D:/VHDs.old/web` },
+        { `web.vhdx`, `web` },
+    }
+
+    for _, test := range tests {
+        if actual := vhdPathWithoutExtension(test.path); actual != test.expected {
+            t.Errorf("vhdPathWithoutExtension(%q): expected %q, got %q", test.path, test.expected, actual)
+        }
+    }
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,624 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+    "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceHypervVM () *schema.Resource {
+    return tfutil.WithResourceXLifecycle(&schema.Resource{
+        CreateContext: resourceHypervVMCreate,
+        ReadContext:   resourceHypervVMRead,
+        UpdateContext: resourceHypervVMUpdate,
+        DeleteContext: resourceHypervVMDelete,
+
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceHypervVMImport,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "generation": &schema.Schema{                          // can only be set when creating the vm
+                Type:     schema.TypeInt,
+                Optional: true,
+                Default:  2,
+                ForceNew: true,
+
+                ValidateFunc: validation.IntInSlice([]int{ 1, 2 }),
+            },
+            "version": &schema.Schema{                             // the configuration version, defaults to the default version of the hyperv-server, can only be upgraded
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: validation.StringMatch(vmVersionRegexp, "must be a configuration version, f.i. \"9.0\""),
+            },
+            "path": &schema.Schema{                                // defaults to the default path of the hyperv-server, can only be set when creating the vm
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                DiffSuppressFunc: diffSuppressVMPath,
+            },
+            "memory_startup_bytes": &schema.Schema{                // can only be changed when the vm is off
+                Type:     schema.TypeInt,
+                Optional: true,
+                Default:  1073741824,   // 1GB
+
+                ValidateFunc: validation.All(
+                    validation.IntAtLeast(33554432),   // 32MB
+                    validation.IntDivisibleBy(2097152),   // 2MB
+                ),
+            },
+            "processor_count": &schema.Schema{                     // can only be changed when the vm is off
+                Type:     schema.TypeInt,
+                Optional: true,
+                Default:  1,
+
+                ValidateFunc: validation.IntBetween(1, 240),
+            },
+            "notes": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+            },
+            "tags": tagsSchema(),                                  // serialized in the vm's notes, together with the provider's "default_tags"
+            "state": &schema.Schema{                               // defaults to the state of the vm, the state is not managed when not configured
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ api.VMStateRunning, api.VMStateOff, api.VMStateSaved, api.VMStatePaused }, false),
+            },
+            "shutdown_timeout": &schema.Schema{                    // seconds to wait for a graceful shutdown of the guest, before turning off the vm
+                Type:     schema.TypeInt,
+                Optional: true,
+                Default:  300,
+
+                ValidateFunc: validation.IntAtLeast(0),
+            },
+            "wait_for_heartbeat": &schema.Schema{                  // when starting the vm, wait for the guest's heartbeat integration service to report OK
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+            },
+
+            // computed
+            "hyperv_name": hypervNameSchema(),
+            "tags_all": tagsAllSchema(),
+            "vm_id": &schema.Schema{                               // the GUID of the vm
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "heartbeat": &schema.Schema{                           // the status of the guest's heartbeat integration service
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+        },
+
+        Timeouts: &schema.ResourceTimeout{                         // includes waiting for the "state" and the heartbeat
+            Create: schema.DefaultTimeout(10 * time.Minute),
+            Update: schema.DefaultTimeout(10 * time.Minute),
+        },
+
+        CustomizeDiff: customdiff.All(
+            customizeDiffHypervName(false),   // the vm is renamed when the "name_prefix" changes
+            customizeDiffTagsAll,
+            customdiff.ForceNewIfChange("version", func(ctx context.Context, old, new, m interface{}) bool {
+                return old.(string) != "" && new.(string) != "" && compareVMVersion(new.(string), old.(string)) < 0   // cannot downgrade
+            }),
+            validateVMState,
+        ),
+    }, &tfutil.ResourceXLifecycle{
+        TypeName:        "hyperv_vm",
+        ID:              resourceHypervVMID,
+        ReadExisting:    resourceHypervVMReadExisting,
+        MatchAttributes: resourceHypervVMMatchAttributes,
+        Adopt:           resourceHypervVMAdopt,
+    })
+}
+
+func validateVMState(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
+    if !diff.HasChange("state") || diff.Get("state").(string) != api.VMStateSaved {
+        return nil
+    }
+
+    // a vm that is off has no memory state to save
+    if diff.Id() == "" {
+        return fmt.Errorf("\"state\": cannot create a vm with 'state = %q', a new vm is off - use 'state = %q' first", api.VMStateSaved, api.VMStateRunning)
+    }
+    if old, _ := diff.GetChange("state"); old.(string) == api.VMStateOff {
+        return fmt.Errorf("\"state\": cannot change the state of a vm from %q to %q - use 'state = %q' first", api.VMStateOff, api.VMStateSaved, api.VMStateRunning)
+    }
+    return nil
+}
+
+func resourceHypervVMCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    name               := d.Get("name").(string)
+    hypervName         := newHypervName(m, name)
+    id                 := fmt.Sprintf("//%s/vms/%s", host, hypervName)
+    generation         := d.Get("generation").(int)
+    version            := d.Get("version").(string)
+    path               := d.Get("path").(string)
+    memoryStartupBytes := d.Get("memory_startup_bytes").(int)
+    processorCount     := d.Get("processor_count").(int)
+    notes              := d.Get("notes").(string)
+    tagsAll            := expandTags(d.Get("tags_all").(map[string]interface{}))
+    state              := d.Get("state").(string)
+
+    version_msg := d.Get("version")
+    path_msg    := d.Get("path")
+    state_msg   := d.Get("state")
+    if version == "" { version_msg = "(computed)" }
+    if path == ""    { path_msg    = "(computed)" }
+    if state == ""   { state_msg   = "(computed)" }
+    log.Printf(`[INFO][terraform-provider-hyperv] creating hyperv_vm %q
+                    [INFO][terraform-provider-hyperv]     name:                 %#v
+                    [INFO][terraform-provider-hyperv]     hyperv_name:          %#v
+                    [INFO][terraform-provider-hyperv]     generation:           %#v
+                    [INFO][terraform-provider-hyperv]     version:              %#v
+                    [INFO][terraform-provider-hyperv]     path:                 %#v
+                    [INFO][terraform-provider-hyperv]     memory_startup_bytes: %#v
+                    [INFO][terraform-provider-hyperv]     processor_count:      %#v
+                    [INFO][terraform-provider-hyperv]     notes:                %#v
+                    [INFO][terraform-provider-hyperv]     tags_all:             %#v
+                    [INFO][terraform-provider-hyperv]     state:                %#v
+`   , id, name, hypervName, generation, version_msg, path_msg, memoryStartupBytes, processorCount, notes, tagsAll, state_msg)
+
+    // create vm
+    vmProperties := new(api.VM)
+    vmProperties.Name               = hypervName
+    vmProperties.Generation         = generation
+    vmProperties.Version            = version
+    vmProperties.Path               = path
+    vmProperties.MemoryStartupBytes = int64(memoryStartupBytes)
+    vmProperties.ProcessorCount     = processorCount
+    vmProperties.Notes              = api.JoinNotes(notes, tagsAll)
+
+    err := c.CreateVM(vmProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_vm %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId(id)
+
+    // set state, a new vm is off
+    var diags diag.Diagnostics
+    if state != "" && state != api.VMStateOff {
+        diags = resourceHypervVMSetState(ctx, d, m, hypervName, d.Timeout(schema.TimeoutCreate))
+        if diags.HasError() {
+            log.Printf("[ERROR][terraform-provider-hyperv] cannot set state %q for hyperv_vm %q\n", state, id)
+            return diags
+        }
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv] created hyperv_vm %q\n", id)
+    return append(diags, resourceHypervVMRead(ctx, d, m)...)
+}
+
+func resourceHypervVMRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id   := d.Id()
+    name := getHypervName(d, m)
+
+    log.Printf("[INFO][terraform-provider-hyperv] reading hyperv_vm %q\n", id)
+
+    // read vm
+    v := new(api.VM)
+    v.Name = name
+
+    vm, err := c.ReadVM(v)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-hyperv] cannot read hyperv_vm %q\n", id)
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vm %q from terraform state\n", id)
+        return nil   // don't return an error to allow terraform refresh to update state
+    }
+
+    // set properties
+    d.Set("name", terraformName(m, vm.Name))
+    d.Set("hyperv_name", vm.Name)
+    d.Set("generation", vm.Generation)
+    d.Set("version", vm.Version)
+    if !vmPathMatches(d.Get("path").(string), vm.Path) {
+        d.Set("path", vm.Path)
+    }
+    d.Set("memory_startup_bytes", vm.MemoryStartupBytes)
+    d.Set("processor_count", vm.ProcessorCount)
+    notes, tags := api.SplitNotes(vm.Notes)
+    d.Set("notes", notes)
+    setTags(d, m, tags)
+    d.Set("state", vm.State)
+    d.Set("vm_id", vm.Id)
+    d.Set("heartbeat", vm.Heartbeat)
+
+    log.Printf("[INFO][terraform-provider-hyperv] read hyperv_vm %q\n", id)
+    return nil
+}
+
+func resourceHypervVMUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id                 := d.Id()
+    oldHypervName, _   := d.GetChange("hyperv_name")
+    name               := d.Get("name").(string)
+    hypervName         := newHypervName(m, name)
+    version            := d.Get("version").(string)
+    memoryStartupBytes := d.Get("memory_startup_bytes").(int)
+    processorCount     := d.Get("processor_count").(int)
+    notes              := d.Get("notes").(string)
+    tagsAll            := expandTags(d.Get("tags_all").(map[string]interface{}))
+    state              := d.Get("state").(string)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] updating hyperv_vm %q
+                    [INFO][terraform-provider-hyperv]     name:                 %#v
+                    [INFO][terraform-provider-hyperv]     hyperv_name:          %#v
+                    [INFO][terraform-provider-hyperv]     version:              %#v
+                    [INFO][terraform-provider-hyperv]     memory_startup_bytes: %#v
+                    [INFO][terraform-provider-hyperv]     processor_count:      %#v
+                    [INFO][terraform-provider-hyperv]     notes:                %#v
+                    [INFO][terraform-provider-hyperv]     tags_all:             %#v
+                    [INFO][terraform-provider-hyperv]     state:                %#v
+`   , id, name, hypervName, version, memoryStartupBytes, processorCount, notes, tagsAll, state)
+
+    // changes in 'shutdown_timeout', 'wait_for_heartbeat' or 'x_lifecycle' only, must not trigger an update in infrastructure
+    if !d.HasChange("hyperv_name") &&
+       !d.HasChange("version") &&
+       !d.HasChange("memory_startup_bytes") &&
+       !d.HasChange("processor_count") &&
+       !d.HasChange("notes") &&
+       !d.HasChange("tags_all") &&
+       !d.HasChange("state") {
+        log.Printf("[INFO][terraform-provider-hyperv] updated hyperv_vm %q in terraform state, no change in infrastructure\n", id)
+        return resourceHypervVMRead(ctx, d, m)
+    }
+
+    // the configuration version, memory and processors can only be changed when the vm is off
+    var diags diag.Diagnostics
+    vmOffRequired := d.HasChange("version") || d.HasChange("memory_startup_bytes") || d.HasChange("processor_count")
+    if vmOffRequired && state == api.VMStateOff && d.HasChange("state") {
+        diags = resourceHypervVMSetState(ctx, d, m, oldHypervName.(string), d.Timeout(schema.TimeoutUpdate))
+        if diags.HasError() {
+            log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_vm %q\n", id)
+            return diags
+        }
+    }
+
+    // update vm
+    v := new(api.VM)
+    v.Name = oldHypervName.(string)
+
+    vmProperties := new(api.VM)
+    vmProperties.Name               = hypervName
+    vmProperties.MemoryStartupBytes = int64(memoryStartupBytes)
+    vmProperties.ProcessorCount     = processorCount
+    vmProperties.Notes              = api.JoinNotes(notes, tagsAll)
+    if d.HasChange("version") {
+        vmProperties.Version = version
+    }
+
+    err := c.UpdateVM(v, vmProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_vm %q\n", id)
+        return append(diags, diag.FromErr(err)...)
+    }
+
+    // set id, the vm may have been renamed
+    id = fmt.Sprintf("//%s/vms/%s", host, hypervName)
+    d.SetId(id)
+
+    // set state
+    if d.HasChange("state") && state != "" && !( vmOffRequired && state == api.VMStateOff ) {
+        diags = append(diags, resourceHypervVMSetState(ctx, d, m, hypervName, d.Timeout(schema.TimeoutUpdate))...)
+        if diags.HasError() {
+            log.Printf("[ERROR][terraform-provider-hyperv] cannot set state %q for hyperv_vm %q\n", state, id)
+            return diags
+        }
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv] updated hyperv_vm %q\n", id)
+    return append(diags, resourceHypervVMRead(ctx, d, m)...)
+}
+
+// resourceHypervVMSetState sets the configured "state" of the vm, and waits for the vm to reach that state
+func resourceHypervVMSetState(ctx context.Context, d *schema.ResourceData, m interface{}, hypervName string, timeout time.Duration) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    state            := d.Get("state").(string)
+    shutdownTimeout  := d.Get("shutdown_timeout").(int)
+    waitForHeartbeat := d.Get("wait_for_heartbeat").(bool) && state == api.VMStateRunning
+
+    log.Printf("[INFO][terraform-provider-hyperv] setting state %q for hyperv_vm %q\n", state, d.Id())
+
+    v := new(api.VM)
+    v.Name = hypervName
+
+    var diags diag.Diagnostics
+    turnedOff, err := c.SetVMState(v, state, shutdownTimeout)
+    if err != nil {
+        return diag.FromErr(err)
+    }
+    if turnedOff {
+        log.Printf("[WARN][terraform-provider-hyperv] turned off hyperv_vm %q, the guest didn't shut down within %d seconds\n", d.Id(), shutdownTimeout)
+        diags = append(diags, diag.Diagnostic{
+            Severity: diag.Warning,
+            Summary:  fmt.Sprintf("Turned off hyperv_vm %q", hypervName),
+            Detail:   fmt.Sprintf("The guest didn't shut down gracefully within the \"shutdown_timeout\" of %d seconds, the vm was turned off.", shutdownTimeout),
+        })
+    }
+
+    // wait for the state and the heartbeat
+    err = waitForVMState(ctx, c, v, state, waitForHeartbeat, timeout)
+    if err != nil {
+        return append(diags, diag.FromErr(err)...)
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv] set state %q for hyperv_vm %q\n", state, d.Id())
+    return diags
+}
+
+func resourceHypervVMDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id   := d.Id()
+    name := getHypervName(d, m)
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleting hyperv_vm %q\n", id)
+
+    // delete vm
+    v := new(api.VM)
+    v.Name = name
+
+    err := c.DeleteVM(v)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot delete hyperv_vm %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vm %q\n", id)
+    return nil
+}
+
+func resourceHypervVMID(d *schema.ResourceData, m interface{}) string {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    return fmt.Sprintf("//%s/vms/%s", host, newHypervName(m, d.Get("name").(string)))
+}
+
+func resourceHypervVMReadExisting(d *schema.ResourceData, m interface{}) (map[string]interface{}, error) {
+    c := m.(*api.HypervClient)
+
+    v := new(api.VM)
+    v.Name = newHypervName(m, d.Get("name").(string))
+
+    vm, err := c.ReadVM(v)
+    if err != nil {
+        return nil, err
+    }
+
+    return map[string]interface{}{
+        "name":                 terraformName(m, vm.Name),
+        "generation":           vm.Generation,
+        "version":              vm.Version,
+        "path":                 vm.Path,
+        "memory_startup_bytes": int(vm.MemoryStartupBytes),
+        "processor_count":      vm.ProcessorCount,
+        "notes":                vm.Notes,   // including the serialized tags
+    }, nil
+}
+
+func resourceHypervVMMatchAttributes(d *schema.ResourceData) []string {
+    // when only the "version", "memory_startup_bytes", "processor_count", "notes" or "tags" properties are different, the existing vm will be imported and updated
+    // remark that "path" is not matched, since hyperv reports the vm's sub-directory
+    return []string{ "generation" }
+}
+
+func resourceHypervVMAdopt(d *schema.ResourceData, m interface{}, existing map[string]interface{}) error {
+    c := m.(*api.HypervClient)
+
+    version            := d.Get("version").(string)
+    memoryStartupBytes := d.Get("memory_startup_bytes").(int)
+    processorCount     := d.Get("processor_count").(int)
+    notes              := api.JoinNotes(d.Get("notes").(string), expandTags(d.Get("tags_all").(map[string]interface{})))
+    if ( version == "" || version == existing["version"].(string) ) &&
+       memoryStartupBytes == existing["memory_startup_bytes"].(int) &&
+       processorCount == existing["processor_count"].(int) &&
+       notes == existing["notes"].(string) {
+        return nil
+    }
+    if version != "" && compareVMVersion(version, existing["version"].(string)) < 0 {
+        return fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVMAdopt()] cannot downgrade the configuration version of existing vm %q from %q to %q", existing["name"].(string), existing["version"].(string), version)
+    }
+
+    // update vm
+    v := new(api.VM)
+    v.Name = newHypervName(m, d.Get("name").(string))
+
+    vmProperties := new(api.VM)
+    vmProperties.Version            = version
+    vmProperties.MemoryStartupBytes = int64(memoryStartupBytes)
+    vmProperties.ProcessorCount     = processorCount
+    vmProperties.Notes              = notes
+
+    return c.UpdateVM(v, vmProperties)
+}
+
+func resourceHypervVMImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    // importID is the id "//<host>/vms/<name>", the real name of the vm including the provider's "name_prefix", or the GUID of the vm
+    importID, err := parseImportID(c, d.Id(), "vms")
+    if err != nil {
+        return nil, err
+    }
+
+    // verify the vm exists, and get its real name
+    var vm *api.VM
+    if isGUID(importID) {
+        vms, err := c.ReadVMs()
+        if err != nil {
+            return nil, err
+        }
+        guid := strings.Trim(importID, "{}")
+        for i := range vms {
+            if strings.EqualFold(vms[i].Id, guid) {
+                vm = &vms[i]
+                break
+            }
+        }
+    } else {
+        v := new(api.VM)
+        v.Name = importID
+
+        vm, err = c.ReadVM(v)
+        if err != nil && !strings.Contains(err.Error(), "cannot find vm") {
+            return nil, err
+        }
+    }
+    if vm == nil {
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVMImport()] cannot find vm %q", importID)
+    }
+    importID = vm.Name
+
+    id := fmt.Sprintf("//%s/vms/%s", host, importID)
+
+    log.Printf("[INFO][terraform-provider-hyperv] importing hyperv_vm %q\n", id)
+
+    // set properties
+    d.Set("name", terraformName(m, importID))
+    d.Set("hyperv_name", importID)
+
+    // set id
+    d.SetId(id)
+
+    return []*schema.ResourceData{ d }, nil
+}
+
+//------------------------------------------------------------------------------
+
+// waitForVMState polls the vm until it reaches the state, and optionally until the guest's heartbeat integration service reports OK
+func waitForVMState(ctx context.Context, c *api.HypervClient, v *api.VM, state string, waitForHeartbeat bool, timeout time.Duration) error {
+    ctx, cancel := context.WithTimeout(ctx, timeout)
+    defer cancel()
+
+    for {
+        vm, err := c.ReadVM(v)
+        if err != nil {
+            return err
+        }
+        if vm.State == state && ( !waitForHeartbeat || vm.HeartbeatOK() ) {
+            return nil
+        }
+
+        select {
+        case <-ctx.Done():
+            if vm.State != state {
+                return fmt.Errorf("[terraform-provider-hyperv/hyperv/waitForVMState()] timeout waiting for vm %q to reach state %q, the vm is %q", v.Name, state, vm.State)
+            }
+            return fmt.Errorf("[terraform-provider-hyperv/hyperv/waitForVMState()] timeout waiting for the heartbeat of vm %q, the heartbeat is %q", v.Name, vm.Heartbeat)
+        case <-time.After(vmStatePollInterval):
+        }
+    }
+}
+
+const vmStatePollInterval = 5 * time.Second
+
+var vmVersionRegexp = regexp.MustCompile(`^[0-9]+\.[0-9]+$`)
+
+// compareVMVersion compares two configuration versions, f.i. "8.0" and "9.0"
+func compareVMVersion(a string, b string) int {
+    as := strings.SplitN(a, ".", 2)
+    bs := strings.SplitN(b, ".", 2)
+    for i := 0; i < 2; i++ {
+        var av, bv int
+        if i < len(as) { av, _ = strconv.Atoi(as[i]) }
+        if i < len(bs) { bv, _ = strconv.Atoi(bs[i]) }
+        if av != bv {
+            if av < bv {
+                return -1
+            }
+            return 1
+        }
+    }
+    return 0
+}
+
+// vmPathMatches returns true when the path of a vm matches the configured path
+//     hyperv creates a sub-directory with the name of the vm when a path is specified for a new vm
+//     the sub-directory is not renamed when the vm is renamed
+func vmPathMatches(configPath string, vmPath string) bool {
+    configPath = strings.TrimRight(configPath, `\`)
+    vmPath     = strings.TrimRight(vmPath, `\`)
+    if configPath == "" {
+        return false
+    }
+    if strings.EqualFold(configPath, vmPath) {
+        return true
+    }
+    i := strings.LastIndex(vmPath, `\`)
+    return i >= 0 && strings.EqualFold(configPath, vmPath[:i])
+}
+
+func diffSuppressVMPath(k, old, new string, d *schema.ResourceData) bool {
+    return new != "" && vmPathMatches(new, old)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,269 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+    "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceHypervVMDvdDrive () *schema.Resource {
+    return &schema.Resource{
+        CreateContext: resourceHypervVMDvdDriveCreate,
+        ReadContext:   resourceHypervVMDvdDriveRead,
+        UpdateContext: resourceHypervVMDvdDriveUpdate,
+        DeleteContext: resourceHypervVMDvdDriveDelete,
+
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceHypervVMDvdDriveImport,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "vm_name": &schema.Schema{                             // the real name of the vm, f.i. 'hyperv_vm.web.hyperv_name'
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "controller_type": &schema.Schema{                     // "ide" for a generation 1 vm, "scsi" for a generation 2 vm - dvd drives can only be added or removed when the vm is off
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "scsi",
+                ForceNew: true,
+
+                ValidateFunc:     validation.StringInSlice([]string{ "ide", "scsi" }, true),
+                StateFunc:        tfutil.StateToLower(),
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "controller_number": &schema.Schema{                   // ide: 0 or 1, scsi: 0 to 3
+                Type:     schema.TypeInt,
+                Optional: true,
+                Default:  0,
+                ForceNew: true,
+
+                ValidateFunc: validation.IntBetween(0, 3),
+            },
+            "controller_location": &schema.Schema{                 // ide: 0 or 1, scsi: 0 to 63
+                Type:     schema.TypeInt,
+                Required: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.IntBetween(0, 63),
+            },
+            "path": &schema.Schema{                                // the path of the iso on the hyperv-server - the iso is changed in place, "" ejects the iso
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+        },
+
+        CustomizeDiff: validateVMDriveController,
+    }
+}
+
+func resourceHypervVMDvdDriveCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    vmName             := d.Get("vm_name").(string)
+    controllerType     := strings.ToLower(d.Get("controller_type").(string))
+    controllerNumber   := d.Get("controller_number").(int)
+    controllerLocation := d.Get("controller_location").(int)
+    path               := d.Get("path").(string)
+    id                 := fmt.Sprintf("//%s/vms/%s/dvd-drives/%s/%d/%d", host, vmName, controllerType, controllerNumber, controllerLocation)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] creating hyperv_vm_dvd_drive %q
+                    [INFO][terraform-provider-hyperv]     vm_name:             %#v
+                    [INFO][terraform-provider-hyperv]     controller_type:     %#v
+                    [INFO][terraform-provider-hyperv]     controller_number:   %#v
+                    [INFO][terraform-provider-hyperv]     controller_location: %#v
+                    [INFO][terraform-provider-hyperv]     path:                %#v
+`   , id, vmName, controllerType, controllerNumber, controllerLocation, path)
+
+    // create dvd drive
+    dvdProperties := new(api.VMDvdDrive)
+    dvdProperties.VMName             = vmName
+    dvdProperties.ControllerType     = controllerType
+    dvdProperties.ControllerNumber   = controllerNumber
+    dvdProperties.ControllerLocation = controllerLocation
+    dvdProperties.Path               = path
+
+    err := c.CreateVMDvdDrive(dvdProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_vm_dvd_drive %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] created hyperv_vm_dvd_drive %q\n", id)
+    return resourceHypervVMDvdDriveRead(ctx, d, m)
+}
+
+func resourceHypervVMDvdDriveRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-hyperv] reading hyperv_vm_dvd_drive %q\n", id)
+
+    // read dvd drive
+    dd := new(api.VMDvdDrive)
+    dd.VMName             = d.Get("vm_name").(string)
+    dd.ControllerType     = strings.ToLower(d.Get("controller_type").(string))
+    dd.ControllerNumber   = d.Get("controller_number").(int)
+    dd.ControllerLocation = d.Get("controller_location").(int)
+
+    dvd, err := c.ReadVMDvdDrive(dd)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-hyperv] cannot read hyperv_vm_dvd_drive %q\n", id)
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vm_dvd_drive %q from terraform state\n", id)
+        return nil   // don't return an error to allow terraform refresh to update state
+    }
+
+    // set properties
+    d.Set("vm_name", dvd.VMName)
+    d.Set("controller_type", dvd.ControllerType)
+    d.Set("controller_number", dvd.ControllerNumber)
+    d.Set("controller_location", dvd.ControllerLocation)
+    d.Set("path", dvd.Path)
+
+    log.Printf("[INFO][terraform-provider-hyperv] read hyperv_vm_dvd_drive %q\n", id)
+    return nil
+}
+
+func resourceHypervVMDvdDriveUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id   := d.Id()
+    path := d.Get("path").(string)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] updating hyperv_vm_dvd_drive %q
+                    [INFO][terraform-provider-hyperv]     path:                %#v
+`   , id, path)
+
+    // update dvd drive
+    dd := new(api.VMDvdDrive)
+    dd.VMName             = d.Get("vm_name").(string)
+    dd.ControllerType     = strings.ToLower(d.Get("controller_type").(string))
+    dd.ControllerNumber   = d.Get("controller_number").(int)
+    dd.ControllerLocation = d.Get("controller_location").(int)
+
+    dvdProperties := new(api.VMDvdDrive)
+    dvdProperties.Path = path
+
+    err := c.UpdateVMDvdDrive(dd, dvdProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_vm_dvd_drive %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv] updated hyperv_vm_dvd_drive %q\n", id)
+    return resourceHypervVMDvdDriveRead(ctx, d, m)
+}
+
+func resourceHypervVMDvdDriveDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleting hyperv_vm_dvd_drive %q\n", id)
+
+    // delete dvd drive
+    dd := new(api.VMDvdDrive)
+    dd.VMName             = d.Get("vm_name").(string)
+    dd.ControllerType     = strings.ToLower(d.Get("controller_type").(string))
+    dd.ControllerNumber   = d.Get("controller_number").(int)
+    dd.ControllerLocation = d.Get("controller_location").(int)
+
+    err := c.DeleteVMDvdDrive(dd)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot delete hyperv_vm_dvd_drive %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vm_dvd_drive %q\n", id)
+    return nil
+}
+
+func resourceHypervVMDvdDriveImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    // importID is the id "//<host>/vms/<vm_name>/dvd-drives/<controller_type>/<controller_number>/<controller_location>", or "<vm_name>/<controller_type>/<controller_number>/<controller_location>"
+    importID, err := parseImportPath(c, d.Id(), "vms")
+    if err != nil {
+        return nil, err
+    }
+    importID = strings.Replace(importID, "/dvd-drives/", "/", 1)
+
+    vmName, controllerType, controllerNumber, controllerLocation, err := parseVMDriveImportID(importID)
+    if err != nil {
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVMDvdDriveImport()] %s", err)
+    }
+    id := fmt.Sprintf("//%s/vms/%s/dvd-drives/%s/%d/%d", host, vmName, controllerType, controllerNumber, controllerLocation)
+
+    // verify the dvd drive exists
+    dd := new(api.VMDvdDrive)
+    dd.VMName             = vmName
+    dd.ControllerType     = controllerType
+    dd.ControllerNumber   = controllerNumber
+    dd.ControllerLocation = controllerLocation
+
+    _, err = c.ReadVMDvdDrive(dd)
+    if err != nil {
+        if !strings.Contains(err.Error(), "cannot find") {   // the vm or the dvd drive
+            return nil, err
+        }
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVMDvdDriveImport()] cannot find dvd drive %q", importID)
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv] importing hyperv_vm_dvd_drive %q\n", id)
+
+    // set properties
+    d.Set("vm_name", vmName)
+    d.Set("controller_type", controllerType)
+    d.Set("controller_number", controllerNumber)
+    d.Set("controller_location", controllerLocation)
+
+    // set id
+    d.SetId(id)
+
+    return []*schema.ResourceData{ d }, nil
+}
+
+//------------------------------------------------------------------------------
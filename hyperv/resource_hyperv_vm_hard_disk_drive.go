@@ -0,0 +1,342 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "strconv"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+    "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceHypervVMHardDiskDrive () *schema.Resource {
+    return &schema.Resource{
+        CreateContext: resourceHypervVMHardDiskDriveCreate,
+        ReadContext:   resourceHypervVMHardDiskDriveRead,
+        UpdateContext: resourceHypervVMHardDiskDriveUpdate,
+        DeleteContext: resourceHypervVMHardDiskDriveDelete,
+
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceHypervVMHardDiskDriveImport,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "vm_name": &schema.Schema{                             // the real name of the vm, f.i. 'hyperv_vm.web.hyperv_name'
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "controller_type": &schema.Schema{                     // hard disk drives can only be added to or removed from an ide controller when the vm is off
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "scsi",
+                ForceNew: true,
+
+                ValidateFunc:     validation.StringInSlice([]string{ "ide", "scsi" }, true),
+                StateFunc:        tfutil.StateToLower(),
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "controller_number": &schema.Schema{                   // ide: 0 or 1, scsi: 0 to 3
+                Type:     schema.TypeInt,
+                Optional: true,
+                Default:  0,
+                ForceNew: true,
+
+                ValidateFunc: validation.IntBetween(0, 3),
+            },
+            "controller_location": &schema.Schema{                 // ide: 0 or 1, scsi: 0 to 63
+                Type:     schema.TypeInt,
+                Required: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.IntBetween(0, 63),
+            },
+            "path": &schema.Schema{                                // the path of the vhd on the hyperv-server, f.i. 'hyperv_vhd.web.path'
+                Type:     schema.TypeString,
+                Required: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+        },
+
+        CustomizeDiff: validateVMDriveController,
+    }
+}
+
+// validateVMDriveController validates the "controller_number" and "controller_location" for the "controller_type" of a vm drive
+func validateVMDriveController(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
+    controller_type := strings.ToLower(diff.Get("controller_type").(string))
+    if controller_type != "ide" {
+        return nil
+    }
+
+    if diff.Get("controller_number").(int) > 1 {
+        return fmt.Errorf("\"controller_number\": must be 0 or 1 for 'controller_type = %q'", controller_type)
+    }
+    if diff.Get("controller_location").(int) > 1 {
+        return fmt.Errorf("\"controller_location\": must be 0 or 1 for 'controller_type = %q'", controller_type)
+    }
+    return nil
+}
+
+func resourceHypervVMHardDiskDriveCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    vmName             := d.Get("vm_name").(string)
+    controllerType     := strings.ToLower(d.Get("controller_type").(string))
+    controllerNumber   := d.Get("controller_number").(int)
+    controllerLocation := d.Get("controller_location").(int)
+    path               := d.Get("path").(string)
+    id                 := fmt.Sprintf("//%s/vms/%s/hard-disk-drives/%s/%d/%d", host, vmName, controllerType, controllerNumber, controllerLocation)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] creating hyperv_vm_hard_disk_drive %q
+                    [INFO][terraform-provider-hyperv]     vm_name:             %#v
+                    [INFO][terraform-provider-hyperv]     controller_type:     %#v
+                    [INFO][terraform-provider-hyperv]     controller_number:   %#v
+                    [INFO][terraform-provider-hyperv]     controller_location: %#v
+                    [INFO][terraform-provider-hyperv]     path:                %#v
+`   , id, vmName, controllerType, controllerNumber, controllerLocation, path)
+
+    // verify the vhd exists
+    v := new(api.VHD)
+    v.Path = path
+
+    _, err := c.ReadVHD(v)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_vm_hard_disk_drive %q\n", id)
+        return diag.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVMHardDiskDriveCreate()] \"path\": cannot find vhd %q", path)
+    }
+
+    // create hard disk drive
+    hddProperties := new(api.VMHardDiskDrive)
+    hddProperties.VMName             = vmName
+    hddProperties.ControllerType     = controllerType
+    hddProperties.ControllerNumber   = controllerNumber
+    hddProperties.ControllerLocation = controllerLocation
+    hddProperties.Path               = path
+
+    err = c.CreateVMHardDiskDrive(hddProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_vm_hard_disk_drive %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] created hyperv_vm_hard_disk_drive %q\n", id)
+    return resourceHypervVMHardDiskDriveRead(ctx, d, m)
+}
+
+func resourceHypervVMHardDiskDriveRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-hyperv] reading hyperv_vm_hard_disk_drive %q\n", id)
+
+    // read hard disk drive
+    h := new(api.VMHardDiskDrive)
+    h.VMName             = d.Get("vm_name").(string)
+    h.ControllerType     = strings.ToLower(d.Get("controller_type").(string))
+    h.ControllerNumber   = d.Get("controller_number").(int)
+    h.ControllerLocation = d.Get("controller_location").(int)
+
+    hdd, err := c.ReadVMHardDiskDrive(h)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-hyperv] cannot read hyperv_vm_hard_disk_drive %q\n", id)
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vm_hard_disk_drive %q from terraform state\n", id)
+        return nil   // don't return an error to allow terraform refresh to update state
+    }
+
+    // detect a disk that was swapped outside terraform
+    var diags diag.Diagnostics
+    statePath := d.Get("path").(string)
+    if statePath != "" && !strings.EqualFold(statePath, hdd.Path) {   // "path" is not set when importing
+        log.Printf("[WARN][terraform-provider-hyperv] disk of hyperv_vm_hard_disk_drive %q was changed outside terraform\n", id)
+        diags = append(diags, diag.Diagnostic{
+            Severity: diag.Warning,
+            Summary:  fmt.Sprintf("Disk of hyperv_vm_hard_disk_drive %q was changed outside terraform", id),
+            Detail:   fmt.Sprintf("The path changed from %q to %q.  The path from the terraform config is restored by the next apply.", statePath, hdd.Path),
+        })
+    }
+
+    // set properties
+    d.Set("vm_name", hdd.VMName)
+    d.Set("controller_type", hdd.ControllerType)
+    d.Set("controller_number", hdd.ControllerNumber)
+    d.Set("controller_location", hdd.ControllerLocation)
+    d.Set("path", hdd.Path)
+
+    log.Printf("[INFO][terraform-provider-hyperv] read hyperv_vm_hard_disk_drive %q\n", id)
+    return diags
+}
+
+func resourceHypervVMHardDiskDriveUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id   := d.Id()
+    path := d.Get("path").(string)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] updating hyperv_vm_hard_disk_drive %q
+                    [INFO][terraform-provider-hyperv]     path:                %#v
+`   , id, path)
+
+    // verify the vhd exists
+    v := new(api.VHD)
+    v.Path = path
+
+    _, err := c.ReadVHD(v)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_vm_hard_disk_drive %q\n", id)
+        return diag.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVMHardDiskDriveUpdate()] \"path\": cannot find vhd %q", path)
+    }
+
+    // update hard disk drive
+    h := new(api.VMHardDiskDrive)
+    h.VMName             = d.Get("vm_name").(string)
+    h.ControllerType     = strings.ToLower(d.Get("controller_type").(string))
+    h.ControllerNumber   = d.Get("controller_number").(int)
+    h.ControllerLocation = d.Get("controller_location").(int)
+
+    hddProperties := new(api.VMHardDiskDrive)
+    hddProperties.Path = path
+
+    err = c.UpdateVMHardDiskDrive(h, hddProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_vm_hard_disk_drive %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv] updated hyperv_vm_hard_disk_drive %q\n", id)
+    return resourceHypervVMHardDiskDriveRead(ctx, d, m)
+}
+
+func resourceHypervVMHardDiskDriveDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleting hyperv_vm_hard_disk_drive %q\n", id)
+
+    // delete hard disk drive
+    h := new(api.VMHardDiskDrive)
+    h.VMName             = d.Get("vm_name").(string)
+    h.ControllerType     = strings.ToLower(d.Get("controller_type").(string))
+    h.ControllerNumber   = d.Get("controller_number").(int)
+    h.ControllerLocation = d.Get("controller_location").(int)
+
+    err := c.DeleteVMHardDiskDrive(h)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot delete hyperv_vm_hard_disk_drive %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vm_hard_disk_drive %q\n", id)
+    return nil
+}
+
+func resourceHypervVMHardDiskDriveImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    // importID is the id "//<host>/vms/<vm_name>/hard-disk-drives/<controller_type>/<controller_number>/<controller_location>", or "<vm_name>/<controller_type>/<controller_number>/<controller_location>"
+    importID, err := parseImportPath(c, d.Id(), "vms")
+    if err != nil {
+        return nil, err
+    }
+    importID = strings.Replace(importID, "/hard-disk-drives/", "/", 1)
+
+    vmName, controllerType, controllerNumber, controllerLocation, err := parseVMDriveImportID(importID)
+    if err != nil {
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVMHardDiskDriveImport()] %s", err)
+    }
+    id := fmt.Sprintf("//%s/vms/%s/hard-disk-drives/%s/%d/%d", host, vmName, controllerType, controllerNumber, controllerLocation)
+
+    // verify the hard disk drive exists
+    h := new(api.VMHardDiskDrive)
+    h.VMName             = vmName
+    h.ControllerType     = controllerType
+    h.ControllerNumber   = controllerNumber
+    h.ControllerLocation = controllerLocation
+
+    _, err = c.ReadVMHardDiskDrive(h)
+    if err != nil {
+        if !strings.Contains(err.Error(), "cannot find") {   // the vm or the hard disk drive
+            return nil, err
+        }
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVMHardDiskDriveImport()] cannot find hard disk drive %q", importID)
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv] importing hyperv_vm_hard_disk_drive %q\n", id)
+
+    // set properties
+    d.Set("vm_name", vmName)
+    d.Set("controller_type", controllerType)
+    d.Set("controller_number", controllerNumber)
+    d.Set("controller_location", controllerLocation)
+
+    // set id
+    d.SetId(id)
+
+    return []*schema.ResourceData{ d }, nil
+}
+
+//------------------------------------------------------------------------------
+
+// parseVMDriveImportID returns the parts of the import ID "<vm_name>/<controller_type>/<controller_number>/<controller_location>" of a vm drive
+func parseVMDriveImportID(importID string) (vmName string, controllerType string, controllerNumber int, controllerLocation int, err error) {
+    parts := strings.Split(importID, "/")
+    if len(parts) != 4 {
+        return "", "", 0, 0, fmt.Errorf("invalid import ID %q, expected \"<vm_name>/<controller_type>/<controller_number>/<controller_location>\"", importID)
+    }
+
+    controllerType = strings.ToLower(parts[1])
+    if controllerType != "ide" && controllerType != "scsi" {
+        return "", "", 0, 0, fmt.Errorf("invalid controller type in import ID %q, expected \"ide\" or \"scsi\"", importID)
+    }
+    controllerNumber, err = strconv.Atoi(parts[2])
+    if err != nil {
+        return "", "", 0, 0, fmt.Errorf("invalid controller number in import ID %q", importID)
+    }
+    controllerLocation, err = strconv.Atoi(parts[3])
+    if err != nil {
+        return "", "", 0, 0, fmt.Errorf("invalid controller location in import ID %q", importID)
+    }
+
+    return parts[0], controllerType, controllerNumber, controllerLocation, nil
+}
+
+//------------------------------------------------------------------------------
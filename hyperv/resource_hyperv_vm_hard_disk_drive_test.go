@@ -0,0 +1,51 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "testing"
+)
+
+//------------------------------------------------------------------------------
+
+func TestParseVMDriveImportID(t *testing.T) {
+    tests := []struct {
+        importID                   string
+        expectedVMName             string
+        expectedControllerType     string
+        expectedControllerNumber   int
+        expectedControllerLocation int
+        expectError                bool
+    }{
+        { "web/scsi/0/1", "web", "scsi", 0, 1, false },
+        { "web/IDE/1/0", "web", "ide", 1, 0, false },
+        { "web/sata/0/1", "", "", 0, 0, true },
+        { "web/scsi/x/1", "", "", 0, 0, true },
+        { "web/scsi/0/x", "", "", 0, 0, true },
+        { "web/scsi/0", "", "", 0, 0, true },
+        { "web", "", "", 0, 0, true },
+    }
+
+    for _, test := range tests {
+        vmName, controllerType, controllerNumber, controllerLocation, err := parseVMDriveImportID(test.importID)
+        if test.expectError {
+            if err == nil {
+                t.Errorf("parseVMDriveImportID(%q): expected an error", test.importID)
+            }
+            continue
+        }
+        if err != nil {
+            t.Errorf("parseVMDriveImportID(%q): unexpected error: %s", test.importID, err)
+            continue
+        }
+        if vmName != test.expectedVMName || controllerType != test.expectedControllerType || controllerNumber != test.expectedControllerNumber || controllerLocation != test.expectedControllerLocation {
+            t.Errorf("parseVMDriveImportID(%q): expected %q, %q, %d, %d, got %q, %q, %d, %d", test.importID, test.expectedVMName, test.expectedControllerType, test.expectedControllerNumber, test.expectedControllerLocation, vmName, controllerType, controllerNumber, controllerLocation)
+        }
+    }
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,357 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+    "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceHypervVMNetworkAdapter () *schema.Resource {
+    return &schema.Resource{
+        CreateContext: resourceHypervVMNetworkAdapterCreate,
+        ReadContext:   resourceHypervVMNetworkAdapterRead,
+        UpdateContext: resourceHypervVMNetworkAdapterUpdate,
+        DeleteContext: resourceHypervVMNetworkAdapterDelete,
+
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceHypervVMNetworkAdapterImport,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "vm_name": &schema.Schema{                             // the real name of the vm, f.i. 'hyperv_vm.web.hyperv_name'
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "name": &schema.Schema{                                // must be unique for the vm
+                Type:     schema.TypeString,
+                Required: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "switch_name": &schema.Schema{                         // the real name of the vswitch, f.i. 'hyperv_vswitch.default.hyperv_name' - "" is not connected
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "mac_address": &schema.Schema{                         // a static mac-address, defaults to a dynamic mac-address - can only be changed when the vm is off
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                ConflictsWith:    []string{ "dynamic_mac_address" },
+                DiffSuppressFunc: tfutil.DiffSuppressMacAddress(),
+            },
+            "dynamic_mac_address": &schema.Schema{                 // changes a static mac-address to a dynamic mac-address - can only be changed when the vm is off
+                Type:     schema.TypeBool,
+                Optional: true,
+                Computed: true,
+
+                ConflictsWith: []string{ "mac_address" },
+            },
+            "vlan_id": &schema.Schema{                             // 0 is untagged
+                Type:     schema.TypeInt,
+                Optional: true,
+                Default:  0,
+
+                ValidateFunc: validation.IntBetween(0, 4094),
+            },
+            "mac_address_spoofing": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+            },
+            "dhcp_guard": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+            },
+            "router_guard": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+            },
+            "device_naming": &schema.Schema{                       // requires a generation 2 vm
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+            },
+
+            // computed
+            "ip_addresses": &schema.Schema{                        // reported by the guest's integration services
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem:     &schema.Schema{ Type: schema.TypeString },
+            },
+        },
+
+        CustomizeDiff: customizeDiffVMNetworkAdapterMacAddress,
+    }
+}
+
+func customizeDiffVMNetworkAdapterMacAddress(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
+    // a dynamic mac-address is assigned by hyperv
+    if diff.Id() != "" && diff.HasChange("dynamic_mac_address") && diff.Get("dynamic_mac_address").(bool) {
+        return diff.SetNewComputed("mac_address")
+    }
+    return nil
+}
+
+func resourceHypervVMNetworkAdapterCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    vmName             := d.Get("vm_name").(string)
+    name               := d.Get("name").(string)
+    id                 := fmt.Sprintf("//%s/vms/%s/network-adapters/%s", host, vmName, name)
+    switchName         := d.Get("switch_name").(string)
+    macAddress         := d.Get("mac_address").(string)
+    vlanId             := d.Get("vlan_id").(int)
+    macAddressSpoofing := d.Get("mac_address_spoofing").(bool)
+    dhcpGuard          := d.Get("dhcp_guard").(bool)
+    routerGuard        := d.Get("router_guard").(bool)
+    deviceNaming       := d.Get("device_naming").(bool)
+
+    macAddress_msg := d.Get("mac_address")
+    if macAddress == "" { macAddress_msg = "(computed)" }
+    log.Printf(`[INFO][terraform-provider-hyperv] creating hyperv_vm_network_adapter %q
+                    [INFO][terraform-provider-hyperv]     vm_name:              %#v
+                    [INFO][terraform-provider-hyperv]     name:                 %#v
+                    [INFO][terraform-provider-hyperv]     switch_name:          %#v
+                    [INFO][terraform-provider-hyperv]     mac_address:          %#v
+                    [INFO][terraform-provider-hyperv]     vlan_id:              %#v
+                    [INFO][terraform-provider-hyperv]     mac_address_spoofing: %#v
+                    [INFO][terraform-provider-hyperv]     dhcp_guard:           %#v
+                    [INFO][terraform-provider-hyperv]     router_guard:         %#v
+                    [INFO][terraform-provider-hyperv]     device_naming:        %#v
+`   , id, vmName, name, switchName, macAddress_msg, vlanId, macAddressSpoofing, dhcpGuard, routerGuard, deviceNaming)
+
+    // create vm network adapter
+    vmnaProperties := new(api.VMNetworkAdapter)
+    vmnaProperties.VMName             = vmName
+    vmnaProperties.Name               = name
+    vmnaProperties.SwitchName         = switchName
+    vmnaProperties.MacAddress         = macAddress
+    vmnaProperties.VlanId             = vlanId
+    vmnaProperties.MacAddressSpoofing = macAddressSpoofing
+    vmnaProperties.DhcpGuard          = dhcpGuard
+    vmnaProperties.RouterGuard        = routerGuard
+    vmnaProperties.DeviceNaming       = deviceNaming
+
+    err := c.CreateVMNetworkAdapter(vmnaProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_vm_network_adapter %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] created hyperv_vm_network_adapter %q\n", id)
+    return resourceHypervVMNetworkAdapterRead(ctx, d, m)
+}
+
+func resourceHypervVMNetworkAdapterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-hyperv] reading hyperv_vm_network_adapter %q\n", id)
+
+    // read vm network adapter
+    vmna := new(api.VMNetworkAdapter)
+    vmna.VMName = d.Get("vm_name").(string)
+    vmna.Name   = d.Get("name").(string)
+
+    vmNetworkAdapter, err := c.ReadVMNetworkAdapter(vmna)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-hyperv] cannot read hyperv_vm_network_adapter %q\n", id)
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vm_network_adapter %q from terraform state\n", id)
+        return nil   // don't return an error to allow terraform refresh to update state
+    }
+
+    // set properties
+    d.Set("vm_name", vmNetworkAdapter.VMName)
+    d.Set("name", vmNetworkAdapter.Name)
+    d.Set("switch_name", vmNetworkAdapter.SwitchName)
+    d.Set("mac_address", vmNetworkAdapter.MacAddress)
+    d.Set("dynamic_mac_address", vmNetworkAdapter.DynamicMacAddress)
+    d.Set("vlan_id", vmNetworkAdapter.VlanId)
+    d.Set("mac_address_spoofing", vmNetworkAdapter.MacAddressSpoofing)
+    d.Set("dhcp_guard", vmNetworkAdapter.DhcpGuard)
+    d.Set("router_guard", vmNetworkAdapter.RouterGuard)
+    d.Set("device_naming", vmNetworkAdapter.DeviceNaming)
+    d.Set("ip_addresses", vmNetworkAdapter.IPAddresses)
+
+    log.Printf("[INFO][terraform-provider-hyperv] read hyperv_vm_network_adapter %q\n", id)
+    return nil
+}
+
+func resourceHypervVMNetworkAdapterUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id                 := d.Id()
+    vmName             := d.Get("vm_name").(string)
+    oldName, _         := d.GetChange("name")
+    name               := d.Get("name").(string)
+    switchName         := d.Get("switch_name").(string)
+    macAddress         := d.Get("mac_address").(string)
+    dynamicMacAddress  := d.Get("dynamic_mac_address").(bool)
+    vlanId             := d.Get("vlan_id").(int)
+    macAddressSpoofing := d.Get("mac_address_spoofing").(bool)
+    dhcpGuard          := d.Get("dhcp_guard").(bool)
+    routerGuard        := d.Get("router_guard").(bool)
+    deviceNaming       := d.Get("device_naming").(bool)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] updating hyperv_vm_network_adapter %q
+                    [INFO][terraform-provider-hyperv]     name:                 %#v
+                    [INFO][terraform-provider-hyperv]     switch_name:          %#v
+                    [INFO][terraform-provider-hyperv]     mac_address:          %#v
+                    [INFO][terraform-provider-hyperv]     dynamic_mac_address:  %#v
+                    [INFO][terraform-provider-hyperv]     vlan_id:              %#v
+                    [INFO][terraform-provider-hyperv]     mac_address_spoofing: %#v
+                    [INFO][terraform-provider-hyperv]     dhcp_guard:           %#v
+                    [INFO][terraform-provider-hyperv]     router_guard:         %#v
+                    [INFO][terraform-provider-hyperv]     device_naming:        %#v
+`   , id, name, switchName, macAddress, dynamicMacAddress, vlanId, macAddressSpoofing, dhcpGuard, routerGuard, deviceNaming)
+
+    // update vm network adapter
+    vmna := new(api.VMNetworkAdapter)
+    vmna.VMName = vmName
+    vmna.Name   = oldName.(string)
+
+    vmnaProperties := new(api.VMNetworkAdapter)
+    vmnaProperties.Name               = name
+    vmnaProperties.SwitchName         = switchName
+    vmnaProperties.VlanId             = vlanId
+    vmnaProperties.MacAddressSpoofing = macAddressSpoofing
+    vmnaProperties.DhcpGuard          = dhcpGuard
+    vmnaProperties.RouterGuard        = routerGuard
+    vmnaProperties.DeviceNaming       = deviceNaming
+    if d.HasChange("mac_address") {
+        vmnaProperties.MacAddress = macAddress   // only when changed, the mac-address is left untouched otherwise
+    }
+    if d.HasChange("dynamic_mac_address") {
+        vmnaProperties.DynamicMacAddress = dynamicMacAddress
+    }
+
+    err := c.UpdateVMNetworkAdapter(vmna, vmnaProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_vm_network_adapter %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id, the adapter may have been renamed
+    id = fmt.Sprintf("//%s/vms/%s/network-adapters/%s", host, vmName, name)
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] updated hyperv_vm_network_adapter %q\n", id)
+    return resourceHypervVMNetworkAdapterRead(ctx, d, m)
+}
+
+func resourceHypervVMNetworkAdapterDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleting hyperv_vm_network_adapter %q\n", id)
+
+    // delete vm network adapter
+    vmna := new(api.VMNetworkAdapter)
+    vmna.VMName = d.Get("vm_name").(string)
+    vmna.Name   = d.Get("name").(string)
+
+    err := c.DeleteVMNetworkAdapter(vmna)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot delete hyperv_vm_network_adapter %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vm_network_adapter %q\n", id)
+    return nil
+}
+
+func resourceHypervVMNetworkAdapterImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    // importID is the id "//<host>/vms/<vm_name>/network-adapters/<name>", or "<vm_name>/<name>"
+    importID, err := parseImportPath(c, d.Id(), "vms")
+    if err != nil {
+        return nil, err
+    }
+    importID = strings.Replace(importID, "/network-adapters/", "/", 1)
+
+    parts := strings.SplitN(importID, "/", 2)
+    if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVMNetworkAdapterImport()] invalid import ID %q, expected \"<vm_name>/<name>\"", importID)
+    }
+
+    // verify the vm network adapter exists, and get its real names
+    vmna := new(api.VMNetworkAdapter)
+    vmna.VMName = parts[0]
+    vmna.Name   = parts[1]
+
+    vmNetworkAdapter, err := c.ReadVMNetworkAdapter(vmna)
+    if err != nil {
+        if !strings.Contains(err.Error(), "cannot find") {   // the vm or the network adapter
+            return nil, err
+        }
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVMNetworkAdapterImport()] cannot find network adapter %q", importID)
+    }
+
+    id := fmt.Sprintf("//%s/vms/%s/network-adapters/%s", host, vmNetworkAdapter.VMName, vmNetworkAdapter.Name)
+
+    log.Printf("[INFO][terraform-provider-hyperv] importing hyperv_vm_network_adapter %q\n", id)
+
+    // set properties
+    d.Set("vm_name", vmNetworkAdapter.VMName)
+    d.Set("name", vmNetworkAdapter.Name)
+
+    // set id
+    d.SetId(id)
+
+    return []*schema.ResourceData{ d }, nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,56 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "testing"
+)
+
+//------------------------------------------------------------------------------
+
+func TestCompareVMVersion(t *testing.T) {
+    tests := []struct {
+        a        string
+        b        string
+        expected int
+    }{
+        { "9.0", "9.0", 0 },
+        { "8.0", "9.0", -1 },
+        { "9.0", "8.0", 1 },
+        { "9.0", "10.0", -1 },   // not compared as strings
+        { "9.1", "9.0", 1 },
+        { "5.0", "5.0", 0 },
+    }
+
+    for _, test := range tests {
+        if actual := compareVMVersion(test.a, test.b); actual != test.expected {
+            t.Errorf("compareVMVersion(%q, %q): expected %d, got %d", test.a, test.b, test.expected, actual)
+        }
+    }
+}
+
+func TestVMPathMatches(t *testing.T) {
+    tests := []struct {
+        configPath string
+        vmPath     string
+        expected   bool
+    }{
+        { `D:\VMs`, `D:\VMs\web`, true },   // hyperv creates a sub-directory with the name of the vm
+        { `D:\VMs\`, `d:\vms`, true },
+        { `D:\VMs\web`, `D:\VMs\web`, true },
+        { `D:\Other`, `D:\VMs\web`, false },
+        { "", `D:\VMs\web`, false },
+    }
+
+    for _, test := range tests {
+        if actual := vmPathMatches(test.configPath, test.vmPath); actual != test.expected {
+            t.Errorf("vmPathMatches(%q, %q): expected %t, got %t", test.configPath, test.vmPath, test.expected, actual)
+        }
+    }
+}
+
+//------------------------------------------------------------------------------
@@ -7,12 +7,16 @@
 package hyperv
 
 import (
+    "context"
     "fmt"
     "log"
+    "net"
     "strings"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
     "github.com/stefaanc/terraform-provider-hyperv/api"
     "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
@@ -21,14 +25,14 @@ import (
 //------------------------------------------------------------------------------
 
 func resourceHypervVSwitch () *schema.Resource {
-    return &schema.Resource{
-        Create: resourceHypervVSwitchCreate,
-        Read:   resourceHypervVSwitchRead,
-        Update: resourceHypervVSwitchUpdate,
-        Delete: resourceHypervVSwitchDelete,
+    return tfutil.WithResourceXLifecycle(&schema.Resource{
+        CreateContext: resourceHypervVSwitchCreate,
+        ReadContext:   resourceHypervVSwitchRead,
+        UpdateContext: resourceHypervVSwitchUpdate,
+        DeleteContext: resourceHypervVSwitchDelete,
 
         Importer: &schema.ResourceImporter{
-            State: resourceHypervVSwitchImport,
+            StateContext: resourceHypervVSwitchImport,
         },
 
         Schema: map[string]*schema.Schema{
@@ -55,6 +59,7 @@ func resourceHypervVSwitch () *schema.Resource {
                 Optional: true,
                 Default:  "",
             },
+            "tags":     tagsSchema(),                              // serialized in the switch's notes, together with the provider's "default_tags"
 
             // config when switch_type is "external"
             "allow_management_os": &schema.Schema{                 // defaults to true when switch_type is "internal", to false when switch_type is "private"
@@ -76,18 +81,114 @@ func resourceHypervVSwitch () *schema.Resource {
 
                 ConflictsWith: []string{ "net_adapter_name" },
             },
+            "enable_iov": &schema.Schema{                          // can only be set when creating an "external" switch
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+                ForceNew: true,
+            },
+            "enable_packet_direct": &schema.Schema{                // can only be set when creating an "external" switch
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+                ForceNew: true,
+            },
+
+            // bandwidth
+            "minimum_bandwidth_mode": &schema.Schema{              // can only be set when creating the switch, defaults to "weight", or to "none" when SR-IOV is enabled
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ValidateFunc:     validation.StringInSlice([]string{ "absolute", "weight", "none" }, true),
+                StateFunc:        tfutil.StateToLower(),
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "default_flow_minimum_bandwidth_weight": &schema.Schema{   // requires 'minimum_bandwidth_mode = "weight"'
+                Type:     schema.TypeInt,
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: validation.IntBetween(0, 100),
+            },
+
+            // config when switch_type is "internal"
+            "nat_name": &schema.Schema{                            // the real name of a NAT for the host network on the switch, f.i. 'nat_name = hyperv_nat.example.hyperv_name'
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+
+            // computed
+            "hyperv_name": hypervNameSchema(),
+            "tags_all": tagsAllSchema(),
+            "iov_enabled": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "iov_support_reasons": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem:     &schema.Schema{ Type: schema.TypeString },
+            },
+            "reconcile_changes": &schema.Schema{                   // the changes to the existing switch when creating with "x_lifecycle.import_and_reconcile", shown in the plan
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem:     &schema.Schema{ Type: schema.TypeString },
+            },
+
+            // destroy
+            "force_destroy": &schema.Schema{                       // disconnects the connected vm network adapters when destroying the switch
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+            },
 
             // lifecycle customizations that are not supported by the 'lifecycle' meta-argument for resources
-            "x_lifecycle": &tfutil.ResourceXLifecycleSchema,
+            "x_lifecycle": tfutil.ExtendResourceXLifecycleSchema(map[string]*schema.Schema{
+                // "import_and_reconcile" imports the resource into the terraform state when creating a resource that already exists, and updates the existing resource to match the config
+                // the existing resource is replaced when the "switch_type" or the "minimum_bandwidth_mode" is different
+                // the changes are shown in the plan as the computed "reconcile_changes"
+                "import_and_reconcile": &schema.Schema{
+                    Type:     schema.TypeBool,
+                    Optional: true,
+                    Default:  false,
+
+                    ConflictsWith: []string{ "x_lifecycle.0.import_if_exists" },
+                },
+                // "allow_management_disruption" allows changes that disconnect the host interface carrying the provider's connection to the hyperv-server
+                // the change is run in a detached scheduled task on the hyperv-server, the resulting state is not read back from the hyperv-server
+                "allow_management_disruption": &schema.Schema{
+                    Type:     schema.TypeBool,
+                    Optional: true,
+                    Default:  false,
+                },
+            }),
                 // remark that as a general rule, "import_if_exists" will fail if any of the properties in the config are not the same as the properties of existing resource
-                // exception to this rule: when only the "notes" property is different, the existing switch will be imported and updated
+                // exception to this rule: when only the "notes" or "tags" properties are different, the existing switch will be imported and updated
         },
 
-        CustomizeDiff: validateConflictsWithSwitchType,
-    }
+        CustomizeDiff: customdiff.All(
+            customizeDiffHypervName(true),
+            validateConflictsWithSwitchType,
+            validateNetAdapter,
+            validateMinimumBandwidth,
+            customizeDiffTagsAll,
+            customizeDiffReconcile,
+        ),
+    }, &tfutil.ResourceXLifecycle{
+        TypeName:        "hyperv_vswitch",
+        ID:              resourceHypervVSwitchID,
+        ReadExisting:    resourceHypervVSwitchReadExisting,
+        MatchAttributes: resourceHypervVSwitchMatchAttributes,
+        Adopt:           resourceHypervVSwitchAdopt,
+    })
 }
 
-func validateConflictsWithSwitchType(diff *schema.ResourceDiff, m interface{}) error {
+func validateConflictsWithSwitchType(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
     switch_type := strings.ToLower(diff.Get("switch_type").(string))
 
     // "allow_management_os"
@@ -111,10 +212,165 @@ func validateConflictsWithSwitchType(diff *schema.ResourceDiff, m interface{}) e
             return fmt.Errorf("\"net_adapter_interface_description\": conflicts with 'switch_type = %q'", switch_type)
         }
     }
+
+    // "enable_iov" and "enable_packet_direct"
+    if switch_type == "private" || switch_type == "internal" {
+        if diff.Get("enable_iov").(bool) {
+            return fmt.Errorf("\"enable_iov\": conflicts with 'switch_type = %q'", switch_type)
+        }
+        if diff.Get("enable_packet_direct").(bool) {
+            return fmt.Errorf("\"enable_packet_direct\": conflicts with 'switch_type = %q'", switch_type)
+        }
+    }
+
+    // "nat_name"
+    if switch_type == "private" || switch_type == "external" {
+        if diff.Get("nat_name").(string) != "" {
+            return fmt.Errorf("\"nat_name\": conflicts with 'switch_type = %q'", switch_type)
+        }
+    }
+    return nil
+}
+
+func validateNetAdapter(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
+    c := m.(*api.HypervClient)
+
+    switch_type := strings.ToLower(diff.Get("switch_type").(string))
+    if switch_type != "external" {
+        return nil
+    }
+
+    // only check the host when the binding of the switch is changing
+    if diff.Id() != "" &&
+       !diff.HasChange("switch_type") &&
+       !diff.HasChange("allow_management_os") &&
+       !diff.HasChange("net_adapter_name") &&
+       !diff.HasChange("net_adapter_interface_description") {
+        return nil
+    }
+
+    name                           := newHypervName(m, diff.Get("name").(string))
+    allowManagementOS              := diff.Get("allow_management_os").(bool)   // false when not configured, same as when creating the switch
+    netAdapterName                 := diff.Get("net_adapter_name").(string)
+    netAdapterInterfaceDescription := diff.Get("net_adapter_interface_description").(string)
+    if netAdapterName == "" && netAdapterInterfaceDescription == "" {
+        return nil   // not known until apply
+    }
+
+    // "net_adapter_name" and "net_adapter_interface_description" must exist
+    netAdapters, err := c.ReadNetAdapters()
+    if err != nil {
+        return err
+    }
+
+    var netAdapter *api.NetAdapter
+    for i := range netAdapters {
+        if ( netAdapterName != "" && strings.EqualFold(netAdapters[i].Name, netAdapterName) ) ||
+           ( netAdapterName == "" && netAdapters[i].InterfaceDescription == netAdapterInterfaceDescription ) {
+            netAdapter = &netAdapters[i]
+            break
+        }
+    }
+    if netAdapter == nil {
+        if netAdapterName != "" {
+            return fmt.Errorf("\"net_adapter_name\": cannot find network adapter %q on the hyperv-server", netAdapterName)
+        }
+        return fmt.Errorf("\"net_adapter_interface_description\": cannot find network adapter %q on the hyperv-server", netAdapterInterfaceDescription)
+    }
+
+    // the network adapter must not be bound to another switch
+    if netAdapter.VSwitchName != "" && !strings.EqualFold(netAdapter.VSwitchName, name) {
+        return fmt.Errorf("network adapter %q is already bound to vswitch %q", netAdapter.Name, netAdapter.VSwitchName)
+    }
+
+    // the network adapter must not carry the management connection when the management OS is not allowed on the switch
+    if !allowManagementOS {
+        var x_lifecycle map[string]interface{}
+        if l := diff.Get("x_lifecycle").([]interface{}); len(l) > 0 && l[0] != nil {
+            x_lifecycle = l[0].(map[string]interface{})
+        }
+
+        managementConnection, err := c.ReadManagementConnection()
+        if err != nil {
+            if allowManagementDisruption(x_lifecycle) {
+                log.Printf("[WARN][terraform-provider-hyperv] cannot verify the management connection for hyperv_vswitch %q\n", name)
+                return nil
+            }
+            return fmt.Errorf("\"allow_management_os\": cannot verify that network adapter %q doesn't carry the management connection to the hyperv-server, binding it with 'allow_management_os = false' could disconnect the hyperv-server - use 'x_lifecycle { allow_management_disruption = true }' to allow this: %s", netAdapter.Name, err)
+        }
+
+        if managementConnection.NetAdapterInterfaceDescription == netAdapter.InterfaceDescription {
+            if allowManagementDisruption(x_lifecycle) {
+                log.Printf("[WARN][terraform-provider-hyperv] network adapter %q carries the management connection to the hyperv-server (%s on %q), binding it with 'allow_management_os = false' will disconnect the hyperv-server\n", netAdapter.Name, managementConnection.IPAddress, managementConnection.InterfaceAlias)
+                return nil
+            }
+            return fmt.Errorf("\"allow_management_os\": network adapter %q carries the management connection to the hyperv-server (%s on %q), binding it with 'allow_management_os = false' would disconnect the hyperv-server - use 'x_lifecycle { allow_management_disruption = true }' to allow this", netAdapter.Name, managementConnection.IPAddress, managementConnection.InterfaceAlias)
+        }
+    }
     return nil
 }
 
-func resourceHypervVSwitchCreate(d *schema.ResourceData, m interface{}) error {
+func validateMinimumBandwidth(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
+    minimumBandwidthMode := strings.ToLower(diff.Get("minimum_bandwidth_mode").(string))   // "" when not configured
+
+    // "default_flow_minimum_bandwidth_weight"
+    if minimumBandwidthMode != "" && minimumBandwidthMode != "weight" {
+        if !diff.GetRawConfig().GetAttr("default_flow_minimum_bandwidth_weight").IsNull() {
+            return fmt.Errorf("\"default_flow_minimum_bandwidth_weight\": conflicts with 'minimum_bandwidth_mode = %q'", minimumBandwidthMode)
+        }
+    }
+    return nil
+}
+
+// customizeDiffReconcile shows the changes to the existing switch in the plan when creating with "import_and_reconcile"
+func customizeDiffReconcile(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
+    c := m.(*api.HypervClient)
+
+    // only check the host when creating with "import_and_reconcile"
+    if diff.Id() != "" {
+        return nil
+    }
+    l := diff.Get("x_lifecycle").([]interface{})
+    if len(l) == 0 || l[0] == nil || !l[0].(map[string]interface{})["import_and_reconcile"].(bool) {
+        return diff.SetNew("reconcile_changes", []string{})
+    }
+    if !diff.NewValueKnown("name") {
+        return diff.SetNewComputed("reconcile_changes")
+    }
+
+    name := newHypervName(m, diff.Get("name").(string))
+
+    vs := new(api.VSwitch)
+    vs.Name = name
+
+    vswitch, err := c.ReadVSwitch(vs)
+    if err != nil {
+        if !strings.Contains(err.Error(), "cannot find vswitch") {
+            return err
+        }
+        return diff.SetNew("reconcile_changes", []string{})   // doesn't exist, will be created
+    }
+
+    vsProperties := new(api.VSwitch)
+    vsProperties.SwitchType                        = strings.ToLower(diff.Get("switch_type").(string))
+    vsProperties.Notes                             = api.JoinNotes(diff.Get("notes").(string), expandTags(diff.Get("tags_all").(map[string]interface{})))
+    vsProperties.AllowManagementOS                 = diff.Get("allow_management_os").(bool)
+    vsProperties.NetAdapterName                    = diff.Get("net_adapter_name").(string)
+    vsProperties.NetAdapterInterfaceDescription    = diff.Get("net_adapter_interface_description").(string)
+    vsProperties.MinimumBandwidthMode              = strings.ToLower(diff.Get("minimum_bandwidth_mode").(string))
+    vsProperties.DefaultFlowMinimumBandwidthWeight = diff.Get("default_flow_minimum_bandwidth_weight").(int)
+    if diff.GetRawConfig().GetAttr("default_flow_minimum_bandwidth_weight").IsNull() {
+        vsProperties.DefaultFlowMinimumBandwidthWeight = vswitch.DefaultFlowMinimumBandwidthWeight   // not configured, keep the existing weight
+    }
+
+    changes, _ := reconcileVSwitchChanges(vswitch, vsProperties)
+    for _, change := range changes {
+        log.Printf("[INFO][terraform-provider-hyperv] \"x_lifecycle.import_and_reconcile\": hyperv_vswitch %q exists, it will be changed: %s\n", name, change)
+    }
+    return diff.SetNew("reconcile_changes", changes)
+}
+
+func resourceHypervVSwitchCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
     c := m.(*api.HypervClient)
 
     host := "localhost"
@@ -122,13 +378,20 @@ func resourceHypervVSwitchCreate(d *schema.ResourceData, m interface{}) error {
         host = c.Host
     }
 
-    id                             := fmt.Sprintf("//%s/vswitches/%s", host, d.Get("name").(string))
-    name                           := d.Get("name").(string)
+    name                           := newHypervName(m, d.Get("name").(string))   // the real name, including the provider's "name_prefix"
+    id                             := fmt.Sprintf("//%s/vswitches/%s", host, name)
     switchType                     := strings.ToLower(d.Get("switch_type").(string))
     notes                          := d.Get("notes").(string)
+    tagsAll                        := expandTags(d.Get("tags_all").(map[string]interface{}))
     allowManagementOS              := d.Get("allow_management_os").(bool)
     netAdapterName                 := d.Get("net_adapter_name").(string)
     netAdapterInterfaceDescription := d.Get("net_adapter_interface_description").(string)
+    enableIov                      := d.Get("enable_iov").(bool)
+    enablePacketDirect             := d.Get("enable_packet_direct").(bool)
+    minimumBandwidthMode           := strings.ToLower(d.Get("minimum_bandwidth_mode").(string))
+    defaultFlowWeight              := d.Get("default_flow_minimum_bandwidth_weight").(int)
+    natName                        := d.Get("nat_name").(string)
+    forceDestroy                   := d.Get("force_destroy").(bool)
     x_lifecycle                    := tfutil.GetResourceDataMap(d, "x_lifecycle")
 
     allowManagementOS_msg              := d.Get("allow_management_os")
@@ -138,110 +401,192 @@ func resourceHypervVSwitchCreate(d *schema.ResourceData, m interface{}) error {
     if netAdapterName == ""                             { netAdapterName_msg                 = "(computed)" }
     if netAdapterInterfaceDescription == ""             { netAdapterInterfaceDescription_msg = "(computed)" }
     log.Printf(`[INFO][terraform-provider-hyperv] creating hyperv_vswitch %q
-                    [INFO][terraform-provider-hyperv]     name:                              %#v
-                    [INFO][terraform-provider-hyperv]     switch_type:                       %#v
-                    [INFO][terraform-provider-hyperv]     notes:                             %#v
-                    [INFO][terraform-provider-hyperv]     allow_management_os:               %#v
-                    [INFO][terraform-provider-hyperv]     net_adapter_name:                  %#v
-                    [INFO][terraform-provider-hyperv]     net_adapter_interface_description: %#v
-`   , id, name, switchType, notes, allowManagementOS_msg, netAdapterName_msg, netAdapterInterfaceDescription_msg)
+                    [INFO][terraform-provider-hyperv]     name:                                  %#v
+                    [INFO][terraform-provider-hyperv]     switch_type:                           %#v
+                    [INFO][terraform-provider-hyperv]     notes:                                 %#v
+                    [INFO][terraform-provider-hyperv]     tags_all:                              %#v
+                    [INFO][terraform-provider-hyperv]     allow_management_os:                   %#v
+                    [INFO][terraform-provider-hyperv]     net_adapter_name:                      %#v
+                    [INFO][terraform-provider-hyperv]     net_adapter_interface_description:     %#v
+                    [INFO][terraform-provider-hyperv]     enable_iov:                            %#v
+                    [INFO][terraform-provider-hyperv]     enable_packet_direct:                  %#v
+                    [INFO][terraform-provider-hyperv]     minimum_bandwidth_mode:                %#v
+                    [INFO][terraform-provider-hyperv]     default_flow_minimum_bandwidth_weight: %#v
+                    [INFO][terraform-provider-hyperv]     nat_name:                              %#v
+`   , id, name, switchType, notes, tagsAll, allowManagementOS_msg, netAdapterName_msg, netAdapterInterfaceDescription_msg, enableIov, enablePacketDirect, minimumBandwidthMode, defaultFlowWeight, natName)
+
+    // verify nat
+    err := verifyVSwitchNat(c, name, natName)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_vswitch %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // verify management connection
+    disruptive, err := verifyVSwitchManagementConnection(c, name, switchType, allowManagementOS, netAdapterName, netAdapterInterfaceDescription, false, x_lifecycle)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_vswitch %q\n", id)
+        return diag.FromErr(err)
+    }
 
     // create vswitch
     vsProperties := new(api.VSwitch)
     vsProperties.Name                           = name
     vsProperties.SwitchType                     = switchType
-    vsProperties.Notes                          = notes
+    vsProperties.Notes                          = api.JoinNotes(notes, tagsAll)
     if switchType == "external" {
         vsProperties.AllowManagementOS              = allowManagementOS
         vsProperties.NetAdapterName                 = netAdapterName
         vsProperties.NetAdapterInterfaceDescription = netAdapterInterfaceDescription
+        vsProperties.EnableIov                      = enableIov
+        vsProperties.EnablePacketDirect             = enablePacketDirect
     }
+    vsProperties.MinimumBandwidthMode              = minimumBandwidthMode
+    vsProperties.DefaultFlowMinimumBandwidthWeight = defaultFlowWeight
 
-    err := c.CreateVSwitch(vsProperties)
-    if err != nil {
-        // lifecycle customizations: import_if_exists
-        if x_lifecycle != nil {
-            import_if_exists := x_lifecycle["import_if_exists"].(bool)
-            if import_if_exists && strings.Contains(err.Error(), "already exists") {
-                log.Printf("[INFO][terraform-provider-hyperv] cannot create hyperv_vswitch %q\n", id)
-                log.Printf("[INFO][terraform-provider-hyperv] importing hyperv_vswitch %q into terraform state\n", id)
-
-                // read vswitch
-                vs := new(api.VSwitch)
-                vs.Name = name
-
-                vswitch, err := c.ReadVSwitch(vs)
-                if err != nil {
-                    log.Printf("[ERROR][terraform-provider-hyperv] cannot read existing hyperv_vswitch %q\n", id)
-                    log.Printf("[ERROR][terraform-provider-hyperv] cannot import hyperv_vswitch %q into terraform state\n", id)
-                    return err
-                }
-
-                if vswitch.SwitchType != switchType ||
-                   ( vswitch.SwitchType == "external" &&
-                     ( vswitch.AllowManagementOS != allowManagementOS ||
-                       ( netAdapterName != "" && vswitch.NetAdapterName != netAdapterName ) ||
-                       ( netAdapterName == "" && vswitch.NetAdapterInterfaceDescription != netAdapterInterfaceDescription ) ) ) {
-                    err = fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVSwitchCreate()] cannot import hyperv_vswitch %q into terraform state when terraform config doesn't match the properties in infrastructure", name)
-
-                    log.Printf(`[ERROR][terraform-provider-hyperv] terraform config for hyperv_vswitch %q doesn't match the existing properties
-                        [ERROR][terraform-provider-hyperv]     name:                              %#v
-                        [ERROR][terraform-provider-hyperv]     switch_type:                       %#v
-                        [ERROR][terraform-provider-hyperv]     notes:                             %#v
-                        [ERROR][terraform-provider-hyperv]     allow_management_os:               %#v
-                        [ERROR][terraform-provider-hyperv]     net_adapter_name:                  %#v
-                        [ERROR][terraform-provider-hyperv]     net_adapter_interface_description: %#v
-`                   , id, vswitch.Name, vswitch.SwitchType, vswitch.Notes, vswitch.AllowManagementOS, vswitch.NetAdapterName, vswitch.NetAdapterInterfaceDescription)
-                    log.Printf("[ERROR][terraform-provider-hyperv] cannot import hyperv_vswitch %q into terraform state\n", id)
-                    return err
-                }
-
-                // update vswitch
-                if vswitch.Notes != notes {
-                    err := c.UpdateVSwitch(vs, vsProperties)
-                    if err != nil {
-                        log.Printf("[ERROR][terraform-provider-hyperv] cannot update existing hyperv_vswitch %q\n", id)
-                        log.Printf("[ERROR][terraform-provider-hyperv] cannot import hyperv_vswitch %q into terraform state\n", id)
-                        return err
-                    }
-                }
-
-                // set computed lifecycle properties
-                x_lifecycle["imported"] = true
-                tfutil.SetResourceDataMap(d, "x_lifecycle", x_lifecycle)
-
-                // set id
-                d.SetId(id)
-
-                log.Printf("[INFO][terraform-provider-hyperv] imported hyperv_vswitch %q into terraform state\n", id)
-                return resourceHypervVSwitchRead(d, m)
+    var diags diag.Diagnostics
+
+    // set computed properties
+    d.Set("reconcile_changes", []string{})
+
+    // lifecycle customizations: import_and_reconcile
+    if x_lifecycle != nil && x_lifecycle["import_and_reconcile"].(bool) {
+        vs := new(api.VSwitch)
+        vs.Name = name
+
+        var changes []string
+        replace := false
+        vswitch, err := c.ReadVSwitch(vs)
+        if err != nil && !strings.Contains(err.Error(), "cannot find vswitch") {
+            log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_vswitch %q\n", id)
+            return diag.FromErr(err)
+        }
+        if err == nil {
+            if d.GetRawConfig().GetAttr("default_flow_minimum_bandwidth_weight").IsNull() {
+                vsProperties.DefaultFlowMinimumBandwidthWeight = vswitch.DefaultFlowMinimumBandwidthWeight   // not configured, keep the existing weight
             }
+            changes, replace = reconcileVSwitchChanges(vswitch, vsProperties)
+            d.Set("reconcile_changes", changes)
         }
 
-        // no lifecycle customizations
-        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_vswitch %q\n", id)
-        return err
+        if err == nil && replace {
+            log.Printf("[INFO][terraform-provider-hyperv] replacing existing hyperv_vswitch %q\n", id)
+
+            // delete vswitch
+            disruptiveDelete, err := verifyVSwitchManagementConnection(c, name, strings.ToLower(vswitch.SwitchType), false, "", "", true, nil)
+            if err != nil {
+                log.Printf("[ERROR][terraform-provider-hyperv] cannot replace existing hyperv_vswitch %q\n", id)
+                return diag.FromErr(err)
+            }
+            if disruptiveDelete {
+                log.Printf("[ERROR][terraform-provider-hyperv] cannot replace existing hyperv_vswitch %q\n", id)
+                return diag.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVSwitchCreate()] cannot replace existing hyperv_vswitch %q when it carries the management connection to the hyperv-server", name)
+            }
+
+            err = c.DeleteVSwitch(vs, forceDestroy)
+            if err != nil {
+                log.Printf("[ERROR][terraform-provider-hyperv] cannot replace existing hyperv_vswitch %q\n", id)
+                return diag.FromErr(err)
+            }
+
+            diags = append(diags, diag.Diagnostic{
+                Severity: diag.Warning,
+                Summary:  fmt.Sprintf("Replaced existing hyperv_vswitch %q", name),
+                Detail:   fmt.Sprintf("\"x_lifecycle.import_and_reconcile\": the existing vswitch was deleted and created again: %s.", strings.Join(changes, ", ")),
+            })
+            // continue with creating the vswitch
+        } else if err == nil {
+            log.Printf("[INFO][terraform-provider-hyperv] importing and reconciling hyperv_vswitch %q into terraform state\n", id)
+
+            if len(changes) > 0 {
+                diags = append(diags, diag.Diagnostic{
+                    Severity: diag.Warning,
+                    Summary:  fmt.Sprintf("Reconciled existing hyperv_vswitch %q", name),
+                    Detail:   fmt.Sprintf("\"x_lifecycle.import_and_reconcile\": the existing vswitch was imported and updated: %s.", strings.Join(changes, ", ")),
+                })
+            }
+
+            // update vswitch
+            vsProperties.Name = ""
+            sameNetAdapter := true
+            if netAdapterName != "" {
+                sameNetAdapter = strings.EqualFold(vswitch.NetAdapterName, netAdapterName)
+            } else if netAdapterInterfaceDescription != "" {
+                sameNetAdapter = vswitch.NetAdapterInterfaceDescription == netAdapterInterfaceDescription
+            }
+            if sameNetAdapter {
+                vsProperties.NetAdapterName                 = ""   // don't re-bind the same network adapter
+                vsProperties.NetAdapterInterfaceDescription = ""
+            }
+
+            if disruptive {
+                err = c.UpdateVSwitchDetached(vs, vsProperties)
+            } else {
+                err = c.UpdateVSwitch(vs, vsProperties)
+            }
+            if err != nil {
+                log.Printf("[ERROR][terraform-provider-hyperv] cannot update existing hyperv_vswitch %q\n", id)
+                log.Printf("[ERROR][terraform-provider-hyperv] cannot import hyperv_vswitch %q into terraform state\n", id)
+                return diag.FromErr(err)
+            }
+
+            // set computed lifecycle properties
+            x_lifecycle["imported"] = true
+            tfutil.SetResourceDataMap(d, "x_lifecycle", x_lifecycle)
+
+            // set id
+            d.SetId(id)
+
+            if disruptive {
+                log.Printf("[WARN][terraform-provider-hyperv] imported and reconciled hyperv_vswitch %q in a detached task, the management connection to the hyperv-server is disrupted\n", id)
+                return append(diags, warnManagementDisruption("Imported and reconciled", name)...)   // the hyperv-server cannot be read after the disconnect
+            }
+
+            log.Printf("[INFO][terraform-provider-hyperv] imported and reconciled hyperv_vswitch %q into terraform state\n", id)
+            return append(diags, resourceHypervVSwitchRead(ctx, d, m)...)
+        }
     }
 
-    // set computed lifecycle properties
-    if x_lifecycle != nil {
-        x_lifecycle["imported"] = false
-        tfutil.SetResourceDataMap(d, "x_lifecycle", x_lifecycle)
+    if disruptive {
+        err = c.CreateVSwitchDetached(vsProperties)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_vswitch %q\n", id)
+            return append(diags, diag.FromErr(err)...)
+        }
+
+        // set computed properties, the hyperv-server cannot be read after the disconnect
+        d.Set("allow_management_os", allowManagementOS)
+        d.Set("minimum_bandwidth_mode", minimumBandwidthMode)
+        d.Set("default_flow_minimum_bandwidth_weight", defaultFlowWeight)
+        d.Set("iov_enabled", enableIov)
+        d.Set("iov_support_reasons", []string{})
+
+        // set id
+        d.SetId(id)
+
+        log.Printf("[WARN][terraform-provider-hyperv] created hyperv_vswitch %q in a detached task, the management connection to the hyperv-server is disrupted\n", id)
+        return append(diags, warnManagementDisruption("Created", name)...)
+    }
+
+    err = c.CreateVSwitch(vsProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_vswitch %q\n", id)
+        return append(diags, diag.FromErr(err)...)
     }
 
     // set id
     d.SetId(id)
 
     log.Printf("[INFO][terraform-provider-hyperv] created hyperv_vswitch %q\n", id)
-    return resourceHypervVSwitchRead(d, m)
+    return append(diags, resourceHypervVSwitchRead(ctx, d, m)...)
 }
 
-func resourceHypervVSwitchRead(d *schema.ResourceData, m interface{}) error {
+func resourceHypervVSwitchRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
     c := m.(*api.HypervClient)
 
-    id          := d.Id()
-    name        := d.Get("name").(string)
-    x_lifecycle := tfutil.GetResourceDataMap(d, "x_lifecycle")
+    id      := d.Id()
+    name    := getHypervName(d, m)
+    natName := d.Get("nat_name").(string)
 
     log.Printf("[INFO][terraform-provider-hyperv] reading hyperv_vswitch %q\n", id)
 
@@ -260,47 +605,109 @@ func resourceHypervVSwitchRead(d *schema.ResourceData, m interface{}) error {
         return nil   // don't return an error to allow terraform refresh to update state
     }
 
+    // warn when the notes were changed outside terraform, they will be restored by the next apply
+    var diags diag.Diagnostics
+    if !d.IsNewResource() && d.Get("switch_type").(string) != "" {   // "switch_type" is not set when importing
+        stateNotes := api.JoinNotes(d.Get("notes").(string), expandTags(d.Get("tags_all").(map[string]interface{})))
+        if vswitch.Notes != stateNotes {
+            log.Printf("[WARN][terraform-provider-hyperv] notes of hyperv_vswitch %q were changed outside terraform\n", id)
+            diags = append(diags, diag.Diagnostic{
+                Severity: diag.Warning,
+                Summary:  fmt.Sprintf("Notes of hyperv_vswitch %q were changed outside terraform", vswitch.Name),
+                Detail:   fmt.Sprintf("The notes changed from %q to %q.  The notes and tags from the terraform config are restored by the next apply.", stateNotes, vswitch.Notes),
+            })
+        }
+    }
+
+    // warn when SR-IOV is enabled on the switch, but cannot be used
+    if vswitch.IovEnabled && len(vswitch.IovSupportReasons) > 0 {
+        log.Printf("[WARN][terraform-provider-hyperv] SR-IOV is enabled but not supported for hyperv_vswitch %q\n", id)
+        diags = append(diags, diag.Diagnostic{
+            Severity: diag.Warning,
+            Summary:  fmt.Sprintf("SR-IOV is enabled but not supported for hyperv_vswitch %q", vswitch.Name),
+            Detail:   fmt.Sprintf("\"enable_iov\": the hyperv-server reports: %s.", strings.Join(vswitch.IovSupportReasons, "; ")),
+        })
+    }
+
     // set properties
-    d.Set("name", vswitch.Name)
+    d.Set("name", terraformName(m, vswitch.Name))
+    d.Set("hyperv_name", vswitch.Name)
     d.Set("switch_type", strings.ToLower(vswitch.SwitchType))
-    d.Set("notes", vswitch.Notes)
+    notes, tags := api.SplitNotes(vswitch.Notes)
+    d.Set("notes", notes)
+    setTags(d, m, tags)
     d.Set("allow_management_os", vswitch.AllowManagementOS)
     d.Set("net_adapter_name", vswitch.NetAdapterName)
     d.Set("net_adapter_interface_description", vswitch.NetAdapterInterfaceDescription)
-    tfutil.SetResourceDataMap(d, "x_lifecycle", x_lifecycle)   // make sure new terraform state includes 'x_lifecycle' from the old terraform state when doing a terraform refresh
+    d.Set("enable_iov", vswitch.IovEnabled)
+    d.Set("enable_packet_direct", vswitch.PacketDirectEnabled)
+    d.Set("minimum_bandwidth_mode", vswitch.MinimumBandwidthMode)
+    d.Set("default_flow_minimum_bandwidth_weight", vswitch.DefaultFlowMinimumBandwidthWeight)
+    d.Set("iov_enabled", vswitch.IovEnabled)
+    d.Set("iov_support_reasons", vswitch.IovSupportReasons)
+    if natName != "" {
+        // the nat is not a property of the vswitch, only check that it still exists
+        //     the prefix of the nat is verified when creating or updating, not on every refresh
+        n := new(api.Nat)
+        n.Name = natName
+
+        _, err := c.ReadNat(n)
+        if err != nil {
+            log.Printf("[INFO][terraform-provider-hyperv] cannot read nat %q for hyperv_vswitch %q\n", natName, id)
+            d.Set("nat_name", "")
+        }
+    }
 
     log.Printf("[INFO][terraform-provider-hyperv] read hyperv_vswitch %q\n", id)
-    return nil
+    return diags
 }
 
-func resourceHypervVSwitchUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceHypervVSwitchUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
     c := m.(*api.HypervClient)
 
     id                             := d.Id()
-    name                           := d.Get("name").(string)
+    name                           := getHypervName(d, m)
     switchType                     := strings.ToLower(d.Get("switch_type").(string))
     notes                          := d.Get("notes").(string)
+    tagsAll                        := expandTags(d.Get("tags_all").(map[string]interface{}))
     allowManagementOS              := d.Get("allow_management_os").(bool)
     netAdapterName                 := d.Get("net_adapter_name").(string)
     netAdapterInterfaceDescription := d.Get("net_adapter_interface_description").(string)
+    defaultFlowWeight              := d.Get("default_flow_minimum_bandwidth_weight").(int)
+    natName                        := d.Get("nat_name").(string)
+    x_lifecycle                    := tfutil.GetResourceDataMap(d, "x_lifecycle")
 
     log.Printf(`[INFO][terraform-provider-hyperv] updating hyperv_vswitch %q
-                    [INFO][terraform-provider-hyperv]     name:                              %#v
-                    [INFO][terraform-provider-hyperv]     switch_type:                       %#v
-                    [INFO][terraform-provider-hyperv]     notes:                             %#v
-                    [INFO][terraform-provider-hyperv]     allow_management_os:               %#v
-                    [INFO][terraform-provider-hyperv]     net_adapter_name:                  %#v
-                    [INFO][terraform-provider-hyperv]     net_adapter_interface_description: %#v
-`   , id, name, switchType, notes, allowManagementOS, netAdapterName, netAdapterInterfaceDescription)
-
-    // changes in 'x_lifecycle' only, must not trigger an update in infrastructure
+                    [INFO][terraform-provider-hyperv]     name:                                  %#v
+                    [INFO][terraform-provider-hyperv]     switch_type:                           %#v
+                    [INFO][terraform-provider-hyperv]     notes:                                 %#v
+                    [INFO][terraform-provider-hyperv]     tags_all:                              %#v
+                    [INFO][terraform-provider-hyperv]     allow_management_os:                   %#v
+                    [INFO][terraform-provider-hyperv]     net_adapter_name:                      %#v
+                    [INFO][terraform-provider-hyperv]     net_adapter_interface_description:     %#v
+                    [INFO][terraform-provider-hyperv]     default_flow_minimum_bandwidth_weight: %#v
+                    [INFO][terraform-provider-hyperv]     nat_name:                              %#v
+`   , id, name, switchType, notes, tagsAll, allowManagementOS, netAdapterName, netAdapterInterfaceDescription, defaultFlowWeight, natName)
+
+    // verify nat
+    if d.HasChange("nat_name") {
+        err := verifyVSwitchNat(c, name, natName)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_vswitch %q\n", id)
+            return diag.FromErr(err)
+        }
+    }
+
+    // changes in 'force_destroy' or 'x_lifecycle' only, must not trigger an update in infrastructure
     if !d.HasChange("switch_type") &&   // strictly speaking, this is not required since 'ForceNew = true', but we add this in case we change to 'ForceNew = false'
        !d.HasChange("notes") &&
+       !d.HasChange("tags_all") &&
        !d.HasChange("allow_management_os") &&
        !d.HasChange("net_adapter_name") &&
-       !d.HasChange("net_adapter_interface_description") {
+       !d.HasChange("net_adapter_interface_description") &&
+       !d.HasChange("default_flow_minimum_bandwidth_weight") {
         log.Printf("[INFO][terraform-provider-hyperv] updated hyperv_vswitch %q in terraform state, no change in infrastructure\n", id)
-        return resourceHypervVSwitchRead(d, m)
+        return resourceHypervVSwitchRead(ctx, d, m)
     }
 
     // update vswitch
@@ -309,56 +716,89 @@ func resourceHypervVSwitchUpdate(d *schema.ResourceData, m interface{}) error {
 
     vsProperties := new(api.VSwitch)
     vsProperties.SwitchType                     = switchType
-    vsProperties.Notes                          = notes
+    vsProperties.Notes                          = api.JoinNotes(notes, tagsAll)
     if switchType == "external" {
         vsProperties.AllowManagementOS              = allowManagementOS
         vsProperties.NetAdapterName                 = netAdapterName
         vsProperties.NetAdapterInterfaceDescription = netAdapterInterfaceDescription
     }
+    vsProperties.DefaultFlowMinimumBandwidthWeight = defaultFlowWeight
+
+    // verify management connection
+    disruptive := false
+    if d.HasChange("allow_management_os") ||
+       d.HasChange("net_adapter_name") ||
+       d.HasChange("net_adapter_interface_description") {
+        var err error
+        disruptive, err = verifyVSwitchManagementConnection(c, name, switchType, allowManagementOS, netAdapterName, netAdapterInterfaceDescription, false, x_lifecycle)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_vswitch %q\n", id)
+            return diag.FromErr(err)
+        }
+    }
+
+    if disruptive {
+        err := c.UpdateVSwitchDetached(vs, vsProperties)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_vswitch %q\n", id)
+            return diag.FromErr(err)
+        }
+
+        log.Printf("[WARN][terraform-provider-hyperv] updated hyperv_vswitch %q in a detached task, the management connection to the hyperv-server is disrupted\n", id)
+        return warnManagementDisruption("Updated", name)   // the hyperv-server cannot be read after the disconnect
+    }
 
     err := c.UpdateVSwitch(vs, vsProperties)
     if err != nil {
         log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_vswitch %q\n", id)
-        return err
+        return diag.FromErr(err)
     }
 
     log.Printf("[INFO][terraform-provider-hyperv] updated hyperv_vswitch %q\n", id)
-    return resourceHypervVSwitchRead(d, m)
+    return resourceHypervVSwitchRead(ctx, d, m)
 }
 
-func resourceHypervVSwitchDelete(d *schema.ResourceData, m interface{}) error {
+func resourceHypervVSwitchDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
     c := m.(*api.HypervClient)
 
-    id          := d.Id()
-    name        := d.Get("name").(string)
-    x_lifecycle := tfutil.GetResourceDataMap(d, "x_lifecycle")
+    id           := d.Id()
+    name         := getHypervName(d, m)
+    forceDestroy := d.Get("force_destroy").(bool)
+    x_lifecycle  := tfutil.GetResourceDataMap(d, "x_lifecycle")
 
-    log.Printf("[INFO][terraform-provider-hyperv] deleting hyperv_vswitch %q\n", id)
+    log.Printf(`[INFO][terraform-provider-hyperv] deleting hyperv_vswitch %q
+                    [INFO][terraform-provider-hyperv]     force_destroy: %#v
+`   , id, forceDestroy)
 
-    // lifecycle customizations: destroy_if_imported
-    if x_lifecycle != nil {
-        imported := x_lifecycle["imported"].(bool)
-        destroy_if_imported := x_lifecycle["destroy_if_imported"].(bool)
-        if imported && !destroy_if_imported {
-            log.Printf("[INFO][terraform-provider-hyperv] hyperv_vswitch %q was imported and must not be deleted from infrastructure\n", id)
-
-            // set id
-            d.SetId("")
-
-            log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vswitch %q from terraform state, no change in infrastructure\n", id)
-            return nil
-        }
+    // verify management connection
+    disruptive, err := verifyVSwitchManagementConnection(c, name, strings.ToLower(d.Get("switch_type").(string)), false, "", "", true, x_lifecycle)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot delete hyperv_vswitch %q\n", id)
+        return diag.FromErr(err)
     }
 
-    // no lifecycle customizations
     // delete vswitch
     vs := new(api.VSwitch)
     vs.Name = name
 
-    err := c.DeleteVSwitch(vs)
+    if disruptive {
+        err = c.DeleteVSwitchDetached(vs, forceDestroy)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-hyperv] cannot delete hyperv_vswitch %q\n", id)
+            return diag.FromErr(err)
+        }
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[WARN][terraform-provider-hyperv] deleted hyperv_vswitch %q in a detached task, the management connection to the hyperv-server is disrupted\n", id)
+        return warnManagementDisruption("Deleted", name)
+    }
+
+    err = c.DeleteVSwitch(vs, forceDestroy)
     if err != nil {
         log.Printf("[ERROR][terraform-provider-hyperv] cannot delete hyperv_vswitch %q\n", id)
-        return err
+        return diag.FromErr(err)
     }
 
     // set id
@@ -368,7 +808,7 @@ func resourceHypervVSwitchDelete(d *schema.ResourceData, m interface{}) error {
     return nil
 }
 
-func resourceHypervVSwitchImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+func resourceHypervVSwitchID(d *schema.ResourceData, m interface{}) string {
     c := m.(*api.HypervClient)
 
     host := "localhost"
@@ -376,10 +816,129 @@ func resourceHypervVSwitchImport(d *schema.ResourceData, m interface{}) ([]*sche
         host = c.Host
     }
 
-    importID := d.Id()   // importID is the name of the vswitch
-    id       := fmt.Sprintf("//%s/vswitches/%s", host, importID)
+    return fmt.Sprintf("//%s/vswitches/%s", host, newHypervName(m, d.Get("name").(string)))
+}
+
+func resourceHypervVSwitchReadExisting(d *schema.ResourceData, m interface{}) (map[string]interface{}, error) {
+    c := m.(*api.HypervClient)
+
+    vs := new(api.VSwitch)
+    vs.Name = newHypervName(m, d.Get("name").(string))
+
+    vswitch, err := c.ReadVSwitch(vs)
+    if err != nil {
+        return nil, err
+    }
+
+    return map[string]interface{}{
+        "name":                                  terraformName(m, vswitch.Name),
+        "switch_type":                           strings.ToLower(vswitch.SwitchType),
+        "notes":                                 vswitch.Notes,   // including the serialized tags
+        "allow_management_os":                   vswitch.AllowManagementOS,
+        "net_adapter_name":                      vswitch.NetAdapterName,
+        "net_adapter_interface_description":     vswitch.NetAdapterInterfaceDescription,
+        "enable_iov":                            vswitch.IovEnabled,
+        "enable_packet_direct":                  vswitch.PacketDirectEnabled,
+        "minimum_bandwidth_mode":                vswitch.MinimumBandwidthMode,
+        "default_flow_minimum_bandwidth_weight": vswitch.DefaultFlowMinimumBandwidthWeight,
+    }, nil
+}
+
+func resourceHypervVSwitchMatchAttributes(d *schema.ResourceData) []string {
+    // when only the "notes" or "tags" properties are different, the existing switch will be imported and updated
+    attributes := []string{ "switch_type" }
+    if strings.ToLower(d.Get("switch_type").(string)) == "external" {
+        attributes = append(attributes, "allow_management_os", "enable_iov", "enable_packet_direct")
+        if d.Get("net_adapter_name").(string) != "" {
+            attributes = append(attributes, "net_adapter_name")
+        } else {
+            attributes = append(attributes, "net_adapter_interface_description")
+        }
+    }
+    if d.Get("minimum_bandwidth_mode").(string) != "" {
+        attributes = append(attributes, "minimum_bandwidth_mode")
+    }
+    if !d.GetRawConfig().GetAttr("default_flow_minimum_bandwidth_weight").IsNull() {
+        attributes = append(attributes, "default_flow_minimum_bandwidth_weight")
+    }
+    return attributes
+}
+
+func resourceHypervVSwitchAdopt(d *schema.ResourceData, m interface{}, existing map[string]interface{}) error {
+    c := m.(*api.HypervClient)
+
+    notes := api.JoinNotes(d.Get("notes").(string), expandTags(d.Get("tags_all").(map[string]interface{})))
+    if existing["notes"].(string) == notes {
+        return nil
+    }
+
+    // update vswitch
+    vs := new(api.VSwitch)
+    vs.Name = newHypervName(m, d.Get("name").(string))
+
+    vsProperties := new(api.VSwitch)
+    vsProperties.SwitchType                        = existing["switch_type"].(string)
+    vsProperties.Notes                             = notes
+    vsProperties.DefaultFlowMinimumBandwidthWeight = existing["default_flow_minimum_bandwidth_weight"].(int)
+    if vsProperties.SwitchType == "external" {
+        vsProperties.AllowManagementOS = existing["allow_management_os"].(bool)
+    }
+
+    return c.UpdateVSwitch(vs, vsProperties)
+}
+
+func resourceHypervVSwitchImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    // importID is the id "//<host>/vswitches/<name>", the real name of the vswitch including the provider's "name_prefix", or the GUID of the vswitch
+    importID, err := parseImportID(c, d.Id(), "vswitches")
+    if err != nil {
+        return nil, err
+    }
+
+    // verify the vswitch exists, and get its real name
+    var vswitch *api.VSwitch
+    if isGUID(importID) {
+        vswitches, err := c.ReadVSwitches()
+        if err != nil {
+            return nil, err
+        }
+        guid := strings.Trim(importID, "{}")
+        for i := range vswitches {
+            if strings.EqualFold(vswitches[i].Id, guid) {
+                vswitch = &vswitches[i]
+                break
+            }
+        }
+    } else {
+        vs := new(api.VSwitch)
+        vs.Name = importID
+
+        vswitch, err = c.ReadVSwitch(vs)
+        if err != nil && !strings.Contains(err.Error(), "cannot find vswitch") {
+            return nil, err
+        }
+    }
+    if vswitch == nil {
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVSwitchImport()] cannot find vswitch %q", importID)
+    }
+    importID = vswitch.Name
+
+    id := fmt.Sprintf("//%s/vswitches/%s", host, importID)
+
+    log.Printf("[INFO][terraform-provider-hyperv] importing hyperv_vswitch %q\n", id)
 
-    log.Printf("[INFO][terraform-provider-hosts] importing hyperv_vswitch %q\n", id)
+    // set properties
+    d.Set("name", terraformName(m, importID))
+    d.Set("hyperv_name", importID)
+
+    // set properties that cannot be read from the infrastructure
+    d.Set("force_destroy", false)
 
     // set id
     d.SetId(id)
@@ -388,3 +947,162 @@ func resourceHypervVSwitchImport(d *schema.ResourceData, m interface{}) ([]*sche
 }
 
 //------------------------------------------------------------------------------
+
+func verifyVSwitchNat(c *api.HypervClient, name string, natName string) error {
+    if natName == "" {
+        return nil
+    }
+
+    n := new(api.Nat)
+    n.Name = natName
+
+    nat, err := c.ReadNat(n)
+    if err != nil {
+        return fmt.Errorf("[terraform-provider-hyperv/hyperv/verifyVSwitchNat()] \"nat_name\": cannot find nat %q", natName)
+    }
+
+    return verifyVSwitchNatPrefix(c, name, nat)
+}
+
+// verifyVSwitchNatPrefix returns an error when the "internal_ip_interface_address_prefix" of the nat doesn't cover the ip configuration of the host interfaces on the vswitch
+// host interfaces without ip addresses in the address family of the nat are not verified, f.i. when the switch is created before its "hyperv_host_ip_address"
+func verifyVSwitchNatPrefix(c *api.HypervClient, name string, nat *api.Nat) error {
+    _, prefix, err := net.ParseCIDR(nat.InternalIPInterfaceAddressPrefix)
+    if err != nil {
+        return fmt.Errorf("[terraform-provider-hyperv/hyperv/verifyVSwitchNatPrefix()] \"nat_name\": cannot parse 'internal_ip_interface_address_prefix = %q' of nat %q", nat.InternalIPInterfaceAddressPrefix, nat.Name)
+    }
+
+    managementOSAdapters, err := c.ReadManagementOSAdapters()
+    if err != nil {
+        return err
+    }
+
+    for _, managementOSAdapter := range managementOSAdapters {
+        if !strings.EqualFold(managementOSAdapter.SwitchName, name) || managementOSAdapter.InterfaceAlias == "" {
+            continue
+        }
+
+        hipc := new(api.HostIPConfiguration)
+        hipc.InterfaceAlias = managementOSAdapter.InterfaceAlias
+
+        hostIPConfiguration, err := c.ReadHostIPConfiguration(hipc)
+        if err != nil {
+            return err
+        }
+
+        var uncovered []string
+        covered := false
+        for _, ipAddress := range hostIPConfiguration.IPAddresses {
+            ip := net.ParseIP(ipAddress.IPAddress)
+            if ip == nil || ( ip.To4() == nil ) != ( prefix.IP.To4() == nil ) {
+                continue   // not in the address family of the nat
+            }
+            if prefix.Contains(ip) {
+                covered = true
+                break
+            }
+            uncovered = append(uncovered, ipAddress.IPAddress)
+        }
+        if !covered && len(uncovered) > 0 {
+            return fmt.Errorf("[terraform-provider-hyperv/hyperv/verifyVSwitchNatPrefix()] \"nat_name\": 'internal_ip_interface_address_prefix = %q' of nat %q doesn't cover the ip addresses %q of host interface %q on vswitch %q", nat.InternalIPInterfaceAddressPrefix, nat.Name, uncovered, hostIPConfiguration.InterfaceAlias, name)
+        }
+    }
+    return nil
+}
+
+// verifyVSwitchManagementConnection returns true when creating, updating or deleting the vswitch disconnects the provider's connection to the hyperv-server
+// this returns an error unless the disruption is allowed using 'x_lifecycle { allow_management_disruption = true }'
+func verifyVSwitchManagementConnection(c *api.HypervClient, name string, switchType string, allowManagementOS bool, netAdapterName string, netAdapterInterfaceDescription string, deleting bool, x_lifecycle map[string]interface{}) (bool, error) {
+    if switchType == "private" {
+        return false, nil   // a private switch cannot carry the management connection
+    }
+    if !deleting && ( switchType != "external" || allowManagementOS ) {
+        return false, nil
+    }
+
+    managementConnection, err := c.ReadManagementConnection()
+    if err != nil {
+        if !allowManagementDisruption(x_lifecycle) {
+            return false, fmt.Errorf("[terraform-provider-hyperv/hyperv/verifyVSwitchManagementConnection()] cannot verify that vswitch %q doesn't carry the management connection to the hyperv-server, this change could disconnect the hyperv-server - use 'x_lifecycle { allow_management_disruption = true }' to allow this: %s", name, err)
+        }
+
+        log.Printf("[WARN][terraform-provider-hyperv] cannot verify the management connection for hyperv_vswitch %q, running the change in a detached task\n", name)
+        return true, nil   // assume the change is disruptive
+    }
+
+    disruptive := strings.EqualFold(managementConnection.VSwitchName, name)
+    if !deleting {
+        if netAdapterName != "" {
+            disruptive = disruptive || strings.EqualFold(managementConnection.NetAdapterName, netAdapterName)
+        } else if netAdapterInterfaceDescription != "" {
+            disruptive = disruptive || managementConnection.NetAdapterInterfaceDescription == netAdapterInterfaceDescription
+        }
+    }
+    if !disruptive {
+        return false, nil
+    }
+
+    if !allowManagementDisruption(x_lifecycle) {
+        return false, fmt.Errorf("[terraform-provider-hyperv/hyperv/verifyVSwitchManagementConnection()] vswitch %q carries the management connection to the hyperv-server (%s on %q), this change would disconnect the hyperv-server - use 'x_lifecycle { allow_management_disruption = true }' to allow this", name, managementConnection.IPAddress, managementConnection.InterfaceAlias)
+    }
+
+    log.Printf("[WARN][terraform-provider-hyperv] vswitch %q carries the management connection to the hyperv-server (%s on %q), running the change in a detached task\n", name, managementConnection.IPAddress, managementConnection.InterfaceAlias)
+    return true, nil
+}
+
+// reconcileVSwitchChanges returns the changes to reconcile an existing vswitch with the properties from the config
+// replace is true when the existing vswitch must be deleted and created again
+func reconcileVSwitchChanges(vswitch *api.VSwitch, vsProperties *api.VSwitch) (changes []string, replace bool) {
+    changes = []string{}
+
+    // changes that require replacing the vswitch
+    if !strings.EqualFold(vswitch.SwitchType, vsProperties.SwitchType) {
+        changes = append(changes, fmt.Sprintf("switch_type: %q => %q (replace)", strings.ToLower(vswitch.SwitchType), strings.ToLower(vsProperties.SwitchType)))
+    }
+    if vsProperties.MinimumBandwidthMode != "" && !strings.EqualFold(vswitch.MinimumBandwidthMode, vsProperties.MinimumBandwidthMode) {
+        changes = append(changes, fmt.Sprintf("minimum_bandwidth_mode: %q => %q (replace)", strings.ToLower(vswitch.MinimumBandwidthMode), strings.ToLower(vsProperties.MinimumBandwidthMode)))
+    }
+    if len(changes) > 0 {
+        return changes, true
+    }
+
+    // changes that update the vswitch
+    if vswitch.Notes != vsProperties.Notes {
+        changes = append(changes, fmt.Sprintf("notes: %q => %q", vswitch.Notes, vsProperties.Notes))
+    }
+    if strings.ToLower(vswitch.SwitchType) == "external" {
+        if vswitch.AllowManagementOS != vsProperties.AllowManagementOS {
+            changes = append(changes, fmt.Sprintf("allow_management_os: %t => %t", vswitch.AllowManagementOS, vsProperties.AllowManagementOS))
+        }
+        if vsProperties.NetAdapterName != "" && !strings.EqualFold(vswitch.NetAdapterName, vsProperties.NetAdapterName) {
+            changes = append(changes, fmt.Sprintf("net_adapter_name: %q => %q", vswitch.NetAdapterName, vsProperties.NetAdapterName))
+        }
+        if vsProperties.NetAdapterName == "" && vsProperties.NetAdapterInterfaceDescription != "" && vswitch.NetAdapterInterfaceDescription != vsProperties.NetAdapterInterfaceDescription {
+            changes = append(changes, fmt.Sprintf("net_adapter_interface_description: %q => %q", vswitch.NetAdapterInterfaceDescription, vsProperties.NetAdapterInterfaceDescription))
+        }
+    }
+    if strings.ToLower(vswitch.MinimumBandwidthMode) == "weight" && vswitch.DefaultFlowMinimumBandwidthWeight != vsProperties.DefaultFlowMinimumBandwidthWeight {
+        changes = append(changes, fmt.Sprintf("default_flow_minimum_bandwidth_weight: %d => %d", vswitch.DefaultFlowMinimumBandwidthWeight, vsProperties.DefaultFlowMinimumBandwidthWeight))
+    }
+    return changes, false
+}
+
+// warnManagementDisruption returns the warning for a change that was run in a detached task
+func warnManagementDisruption(action string, name string) diag.Diagnostics {
+    return diag.Diagnostics{
+        diag.Diagnostic{
+            Severity: diag.Warning,
+            Summary:  fmt.Sprintf("%s hyperv_vswitch %q in a detached task", action, name),
+            Detail:   "The vswitch carries the management connection to the hyperv-server, the change was run in a detached task and the connection is disrupted.  The result cannot be read until the hyperv-server can be reached again.",
+        },
+    }
+}
+
+func allowManagementDisruption(x_lifecycle map[string]interface{}) bool {
+    if x_lifecycle == nil {
+        return false
+    }
+    return x_lifecycle["allow_management_disruption"].(bool)
+}
+
+//------------------------------------------------------------------------------
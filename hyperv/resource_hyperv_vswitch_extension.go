@@ -0,0 +1,254 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+    "github.com/stefaanc/terraform-provider-hyperv/hyperv/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceHypervVSwitchExtension () *schema.Resource {
+    return &schema.Resource{
+        CreateContext: resourceHypervVSwitchExtensionCreate,
+        ReadContext:   resourceHypervVSwitchExtensionRead,
+        UpdateContext: resourceHypervVSwitchExtensionUpdate,
+        DeleteContext: resourceHypervVSwitchExtensionDelete,
+
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceHypervVSwitchExtensionImport,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "switch_name": &schema.Schema{                         // the real name of the vswitch, f.i. 'hyperv_vswitch.example.hyperv_name'
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "extension_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                DiffSuppressFunc: tfutil.DiffSuppressCase(),
+            },
+            "enabled": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  true,
+            },
+
+            // computed
+            "running": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "vendor": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "version": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "extension_type": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+        },
+    }
+}
+
+func resourceHypervVSwitchExtensionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id            := fmt.Sprintf("//%s/vswitches/%s/extensions/%s", host, d.Get("switch_name").(string), d.Get("extension_name").(string))
+    switchName    := d.Get("switch_name").(string)
+    extensionName := d.Get("extension_name").(string)
+    enabled       := d.Get("enabled").(bool)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] creating hyperv_vswitch_extension %q
+                    [INFO][terraform-provider-hyperv]     switch_name:    %#v
+                    [INFO][terraform-provider-hyperv]     extension_name: %#v
+                    [INFO][terraform-provider-hyperv]     enabled:        %#v
+`   , id, switchName, extensionName, enabled)
+
+    // extensions are installed on every vswitch, "creating" an extension only changes its enabled-state
+    vse := new(api.VSwitchExtension)
+    vse.SwitchName = switchName
+    vse.Name       = extensionName
+
+    vseProperties := new(api.VSwitchExtension)
+    vseProperties.Enabled = enabled
+
+    err := c.UpdateVSwitchExtension(vse, vseProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot create hyperv_vswitch_extension %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] created hyperv_vswitch_extension %q\n", id)
+    return resourceHypervVSwitchExtensionRead(ctx, d, m)
+}
+
+func resourceHypervVSwitchExtensionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id            := d.Id()
+    switchName    := d.Get("switch_name").(string)
+    extensionName := d.Get("extension_name").(string)
+
+    log.Printf("[INFO][terraform-provider-hyperv] reading hyperv_vswitch_extension %q\n", id)
+
+    // read vswitch extension
+    vse := new(api.VSwitchExtension)
+    vse.SwitchName = switchName
+    vse.Name       = extensionName
+
+    vswitchExtension, err := c.ReadVSwitchExtension(vse)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-hyperv] cannot read hyperv_vswitch_extension %q\n", id)
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vswitch_extension %q from terraform state\n", id)
+        return nil   // don't return an error to allow terraform refresh to update state
+    }
+
+    // set properties
+    d.Set("switch_name", vswitchExtension.SwitchName)
+    d.Set("extension_name", vswitchExtension.Name)
+    d.Set("enabled", vswitchExtension.Enabled)
+    d.Set("running", vswitchExtension.Running)
+    d.Set("vendor", vswitchExtension.Vendor)
+    d.Set("version", vswitchExtension.Version)
+    d.Set("extension_type", vswitchExtension.ExtensionType)
+
+    log.Printf("[INFO][terraform-provider-hyperv] read hyperv_vswitch_extension %q\n", id)
+    return nil
+}
+
+func resourceHypervVSwitchExtensionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id            := d.Id()
+    switchName    := d.Get("switch_name").(string)
+    extensionName := d.Get("extension_name").(string)
+    enabled       := d.Get("enabled").(bool)
+
+    log.Printf(`[INFO][terraform-provider-hyperv] updating hyperv_vswitch_extension %q
+                    [INFO][terraform-provider-hyperv]     switch_name:    %#v
+                    [INFO][terraform-provider-hyperv]     extension_name: %#v
+                    [INFO][terraform-provider-hyperv]     enabled:        %#v
+`   , id, switchName, extensionName, enabled)
+
+    // update vswitch extension
+    vse := new(api.VSwitchExtension)
+    vse.SwitchName = switchName
+    vse.Name       = extensionName
+
+    vseProperties := new(api.VSwitchExtension)
+    vseProperties.Enabled = enabled
+
+    err := c.UpdateVSwitchExtension(vse, vseProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot update hyperv_vswitch_extension %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    log.Printf("[INFO][terraform-provider-hyperv] updated hyperv_vswitch_extension %q\n", id)
+    return resourceHypervVSwitchExtensionRead(ctx, d, m)
+}
+
+func resourceHypervVSwitchExtensionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+    c := m.(*api.HypervClient)
+
+    id            := d.Id()
+    switchName    := d.Get("switch_name").(string)
+    extensionName := d.Get("extension_name").(string)
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleting hyperv_vswitch_extension %q\n", id)
+
+    // extensions cannot be removed from a vswitch, "deleting" an extension disables it
+    vse := new(api.VSwitchExtension)
+    vse.SwitchName = switchName
+    vse.Name       = extensionName
+
+    vseProperties := new(api.VSwitchExtension)
+    vseProperties.Enabled = false
+
+    err := c.UpdateVSwitchExtension(vse, vseProperties)
+    if err != nil {
+        if strings.Contains(err.Error(), "cannot find vswitch") {
+            // the vswitch or the extension is already gone
+            d.SetId("")
+
+            log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vswitch_extension %q from terraform state, no change in infrastructure\n", id)
+            return nil
+        }
+
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot delete hyperv_vswitch_extension %q\n", id)
+        return diag.FromErr(err)
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-hyperv] deleted hyperv_vswitch_extension %q\n", id)
+    return nil
+}
+
+func resourceHypervVSwitchExtensionImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+    c := m.(*api.HypervClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    importID := d.Id()   // importID is "<switch_name>/<extension_name>"
+    i := strings.LastIndex(importID, "/")
+    if i <= 0 || i == len(importID) - 1 {
+        return nil, fmt.Errorf("[terraform-provider-hyperv/hyperv/resourceHypervVSwitchExtensionImport()] invalid import ID %q, expected \"<switch_name>/<extension_name>\"", importID)
+    }
+    switchName    := importID[:i]
+    extensionName := importID[i+1:]
+    id            := fmt.Sprintf("//%s/vswitches/%s/extensions/%s", host, switchName, extensionName)
+
+    log.Printf("[INFO][terraform-provider-hyperv] importing hyperv_vswitch_extension %q\n", id)
+
+    // set properties
+    d.Set("switch_name", switchName)
+    d.Set("extension_name", extensionName)
+
+    // set id
+    d.SetId(id)
+
+    return []*schema.ResourceData{ d }, nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,133 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/go-cty/cty"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+//------------------------------------------------------------------------------
+
+func TestResourceHypervVSwitchCustomizeDiff(t *testing.T) {
+    tests := []struct {
+        config      map[string]interface{}
+        expectError string
+    }{
+        {
+            config:      map[string]interface{}{ "name": "test", "switch_type": "private", "allow_management_os": true },
+            expectError: `"allow_management_os": conflicts with 'switch_type = "private"'`,
+        },
+        {
+            config:      map[string]interface{}{ "name": "test", "switch_type": "internal", "net_adapter_name": "Ethernet" },
+            expectError: `"net_adapter_name": conflicts with 'switch_type = "internal"'`,
+        },
+        {
+            config:      map[string]interface{}{ "name": "test", "switch_type": "external", "nat_name": "test" },
+            expectError: `"nat_name": conflicts with 'switch_type = "external"'`,
+        },
+        {
+            config:      map[string]interface{}{ "name": "test", "switch_type": "private", "minimum_bandwidth_mode": "none", "default_flow_minimum_bandwidth_weight": 10 },
+            expectError: `"default_flow_minimum_bandwidth_weight": conflicts with 'minimum_bandwidth_mode = "none"'`,
+        },
+        {
+            config:      map[string]interface{}{ "name": "test", "switch_type": "private", "minimum_bandwidth_mode": "weight", "default_flow_minimum_bandwidth_weight": 10 },
+            expectError: "",
+        },
+        {
+            config:      map[string]interface{}{ "name": "test", "switch_type": "internal" },
+            expectError: "",
+        },
+    }
+
+    for _, test := range tests {
+        _, err := testResourceDiff(resourceHypervVSwitch(), test.config, testClient(""))
+        if test.expectError == "" {
+            if err != nil {
+                t.Errorf("diff for %#v: unexpected error: %s", test.config, err)
+            }
+            continue
+        }
+        if err == nil || !strings.Contains(err.Error(), test.expectError) {
+            t.Errorf("diff for %#v: expected error %q, got %v", test.config, test.expectError, err)
+        }
+    }
+}
+
+func TestResourceHypervVSwitchCustomizeDiffHypervName(t *testing.T) {
+    diff, err := testResourceDiff(resourceHypervVSwitch(), map[string]interface{}{ "name": "test", "switch_type": "private" }, testClient("ws1-"))
+    if err != nil {
+        t.Fatalf("unexpected error: %s", err)
+    }
+
+    if attr := diff.Attributes["hyperv_name"]; attr == nil || attr.New != "ws1-test" {
+        t.Errorf("expected 'hyperv_name = %q', got %#v", "ws1-test", attr)
+    }
+}
+
+func TestResourceHypervVSwitchImport(t *testing.T) {
+    tests := []struct {
+        importID    string
+        expectError string
+    }{
+        { "//other-host/vswitches/test", `is for host "other-host", but the provider is connected to host "test-host"` },
+        { "//test-host/vms/test", `invalid import ID "//test-host/vms/test"` },
+        { "//test-host/vswitches/test/extensions/test", `invalid import ID "//test-host/vswitches/test/extensions/test"` },
+    }
+
+    for _, test := range tests {
+        d := schema.TestResourceDataRaw(t, resourceHypervVSwitch().Schema, map[string]interface{}{})
+        d.SetId(test.importID)
+
+        _, err := resourceHypervVSwitchImport(context.Background(), d, testClient(""))
+        if err == nil || !strings.Contains(err.Error(), test.expectError) {
+            t.Errorf("import %q: expected error %q, got %v", test.importID, test.expectError, err)
+        }
+    }
+}
+
+//------------------------------------------------------------------------------
+
+// testResourceDiff returns the diff for creating a resource with a config, including the changes from the "CustomizeDiff" of the resource
+//     the config only has attributes, absent attributes are null and absent blocks are empty
+func testResourceDiff(r *schema.Resource, config map[string]interface{}, m interface{}) (*terraform.InstanceDiff, error) {
+    block := r.CoreConfigSchema()
+
+    values := make(map[string]cty.Value)
+    for name, attribute := range block.Attributes {
+        values[name] = cty.NullVal(attribute.Type)
+    }
+    for name, blockType := range block.BlockTypes {
+        values[name] = cty.ListValEmpty(blockType.Block.ImpliedType())
+    }
+    for name, value := range config {
+        switch v := value.(type) {
+        case string:
+            values[name] = cty.StringVal(v)
+        case bool:
+            values[name] = cty.BoolVal(v)
+        case int:
+            values[name] = cty.NumberIntVal(int64(v))
+        default:
+            return nil, fmt.Errorf("unsupported value %#v for %q", value, name)
+        }
+    }
+    rawConfig := cty.ObjectVal(values)
+
+    state := new(terraform.InstanceState)
+    state.RawConfig = rawConfig
+
+    return r.SimpleDiff(context.Background(), state, terraform.NewResourceConfigShimmed(rawConfig, block), m)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,103 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package hyperv
+
+import (
+    "context"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
+)
+
+//------------------------------------------------------------------------------
+
+// resources supporting tags have a "tags" argument and a computed "tags_all" attribute
+//     "tags_all" includes the provider's "default_tags", this is what is serialized in the 'Notes' of the hyperv object
+// remark that "tags_all" must be set in the resource's 'CustomizeDiff' using 'customizeDiffTagsAll'
+
+func tagsSchema() *schema.Schema {
+    return &schema.Schema{
+        Type:     schema.TypeMap,
+        Optional: true,
+        Elem:     &schema.Schema{ Type: schema.TypeString },
+    }
+}
+
+func tagsAllSchema() *schema.Schema {
+    return &schema.Schema{
+        Type:     schema.TypeMap,
+        Computed: true,
+        Elem:     &schema.Schema{ Type: schema.TypeString },
+    }
+}
+
+func tagsComputedSchema() *schema.Schema {   // for data-sources
+    return &schema.Schema{
+        Type:     schema.TypeMap,
+        Computed: true,
+        Elem:     &schema.Schema{ Type: schema.TypeString },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func customizeDiffTagsAll(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
+    c := m.(*api.HypervClient)
+
+    if !diff.NewValueKnown("tags") {
+        return diff.SetNewComputed("tags_all")
+    }
+
+    tagsAll := mergeTags(c.DefaultTags, expandTags(diff.Get("tags").(map[string]interface{})))
+    if len(tagsAll) == 0 && len(diff.Get("tags_all").(map[string]interface{})) == 0 {
+        return nil
+    }
+    return diff.SetNew("tags_all", tagsAll)
+}
+
+func mergeTags(defaultTags map[string]string, tags map[string]string) map[string]string {
+    tagsAll := make(map[string]string)
+    for k, v := range defaultTags {
+        tagsAll[k] = v
+    }
+    for k, v := range tags {
+        tagsAll[k] = v
+    }
+    return tagsAll
+}
+
+// setTags sets "tags" and "tags_all" from the tags read from the hyperv object
+// the provider's "default_tags" are excluded from "tags", unless they are configured in "tags"
+func setTags(d *schema.ResourceData, m interface{}, tagsAll map[string]string) {
+    c := m.(*api.HypervClient)
+
+    configured := expandTags(d.Get("tags").(map[string]interface{}))
+
+    tags := make(map[string]string)
+    for k, v := range tagsAll {
+        if _, ok := configured[k]; !ok {
+            if dv, ok := c.DefaultTags[k]; ok && dv == v {
+                continue
+            }
+        }
+        tags[k] = v
+    }
+
+    d.Set("tags", tags)
+    d.Set("tags_all", tagsAll)
+}
+
+func expandTags(m map[string]interface{}) map[string]string {
+    tags := make(map[string]string)
+    for k, v := range m {
+        tags[k] = v.(string)
+    }
+    return tags
+}
+
+//------------------------------------------------------------------------------
@@ -9,7 +9,7 @@ package tfutil
 import (
     "strings"
 
-    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 //------------------------------------------------------------------------------
@@ -66,6 +66,21 @@ var ResourceXLifecycleSchema schema.Schema = schema.Schema{
     },
 }
 
+// ExtendResourceXLifecycleSchema returns a copy of ResourceXLifecycleSchema with additional resource-specific lifecycle customizations
+func ExtendResourceXLifecycleSchema(extensions map[string]*schema.Schema) *schema.Schema {
+    s := make(map[string]*schema.Schema)
+    for k, v := range ResourceXLifecycleSchema.Elem.(*schema.Resource).Schema {
+        s[k] = v
+    }
+    for k, v := range extensions {
+        s[k] = v
+    }
+
+    x := ResourceXLifecycleSchema
+    x.Elem = &schema.Resource{ Schema: s }
+    return &x
+}
+
 //------------------------------------------------------------------------------
 
 func StateToLower() schema.SchemaStateFunc {
@@ -85,11 +100,22 @@ func DiffSuppressCase() schema.SchemaDiffSuppressFunc {
     }
 }
 
+func DiffSuppressMacAddress() schema.SchemaDiffSuppressFunc {
+    return func(k, old, new string, d *schema.ResourceData) bool {
+        // mac-addresses are compared ignoring case and separators, f.i. "00-15-5D-01-02-03" equals "00155d010203"
+        normalize := strings.NewReplacer("-", "", ":", "", ".", "")
+        if strings.ToLower(normalize.Replace(old)) == strings.ToLower(normalize.Replace(new)) {
+            return true
+        }
+        return false
+    }
+}
+
 //------------------------------------------------------------------------------
 
 func GetResourceDataMap(d *schema.ResourceData, name string) (m map[string]interface{}) {
     list := d.Get(name).([]interface{})
-    if len(list) > 0 {
+    if len(list) > 0 && list[0] != nil {
         m = list[0].(map[string]interface{})
     }
     return m
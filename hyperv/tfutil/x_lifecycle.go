@@ -0,0 +1,294 @@
+//
+// Copyright (c) 2019 Sean Reynolds, Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-hyperv
+//
+package tfutil
+
+import (
+    "context"
+    "errors"
+    "log"
+    "reflect"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+//------------------------------------------------------------------------------
+
+// ResourceXLifecycle describes a resource for the generic implementation of the 'x_lifecycle' customizations for resources
+//     "import_if_exists", "imported" and "destroy_if_imported"
+type ResourceXLifecycle struct {
+    TypeName        string   // the terraform type of the resource, f.i. "hyperv_vswitch"
+
+    // ID returns the id of the resource from the config
+    ID              func(d *schema.ResourceData, m interface{}) string
+
+    // ReadExisting reads the existing resource from the infrastructure, returning the values of the resource's attributes
+    ReadExisting    func(d *schema.ResourceData, m interface{}) (map[string]interface{}, error)
+
+    // MatchAttributes returns the attributes that must be the same in the config and in the existing resource to allow importing the existing resource
+    // remark that strings are compared ignoring case
+    MatchAttributes func(d *schema.ResourceData) []string
+
+    // Adopt updates the existing resource for the attributes that don't need to match (optional)
+    Adopt           func(d *schema.ResourceData, m interface{}, existing map[string]interface{}) error
+
+    // AlreadyExists returns true when the error from creating the resource means the resource already exists (optional)
+    // defaults to checking the error for "already exists"
+    AlreadyExists   func(err error) bool
+}
+
+// WithResourceXLifecycle adds the 'x_lifecycle' customizations to a resource
+// the 'x_lifecycle' schema is added when the resource doesn't define its own (extended) schema
+func WithResourceXLifecycle(r *schema.Resource, x *ResourceXLifecycle) *schema.Resource {
+    if _, ok := r.Schema["x_lifecycle"]; !ok {
+        r.Schema["x_lifecycle"] = &ResourceXLifecycleSchema
+    }
+
+    r.CreateContext = x.create(r.CreateContext, r.ReadContext)
+    r.ReadContext   = x.read(r.ReadContext)
+    r.DeleteContext = x.delete(r.DeleteContext)
+    return r
+}
+
+func (x *ResourceXLifecycle) create(create schema.CreateContextFunc, read schema.ReadContextFunc) schema.CreateContextFunc {
+    return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+        x_lifecycle := GetResourceDataMap(d, "x_lifecycle")
+
+        diags := create(ctx, d, m)
+        if diags.HasError() {
+            // lifecycle customizations: import_if_exists
+            if x_lifecycle != nil {
+                import_if_exists := x_lifecycle["import_if_exists"].(bool)
+                if import_if_exists && x.alreadyExists(diags) {
+                    return x.importExisting(ctx, d, m, read, x_lifecycle)
+                }
+            }
+
+            // no lifecycle customizations
+            return diags
+        }
+
+        // set computed lifecycle properties
+        x_lifecycle = GetResourceDataMap(d, "x_lifecycle")
+        if x_lifecycle != nil {
+            if imported, _ := x_lifecycle["imported"].(bool); !imported {   // can be set by resource-specific lifecycle customizations
+                x_lifecycle["imported"] = false
+                SetResourceDataMap(d, "x_lifecycle", x_lifecycle)
+            }
+        }
+        return diags
+    }
+}
+
+func (x *ResourceXLifecycle) importExisting(ctx context.Context, d *schema.ResourceData, m interface{}, read schema.ReadContextFunc, x_lifecycle map[string]interface{}) diag.Diagnostics {
+    id := x.ID(d, m)
+
+    log.Printf("[INFO][terraform-provider-hyperv] cannot create %s %q\n", x.TypeName, id)
+    log.Printf("[INFO][terraform-provider-hyperv] importing %s %q into terraform state\n", x.TypeName, id)
+
+    // read existing resource
+    existing, err := x.ReadExisting(d, m)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot read existing %s %q\n", x.TypeName, id)
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot import %s %q into terraform state\n", x.TypeName, id)
+        return diag.FromErr(err)
+    }
+
+    // compare config with existing resource
+    mismatches := []string{}
+    for _, k := range x.MatchAttributes(d) {
+        if !matchAttribute(d.Get(k), existing[k]) {
+            log.Printf("[ERROR][terraform-provider-hyperv]     %s: config %#v, existing %#v\n", k, d.Get(k), existing[k])
+            mismatches = append(mismatches, k)
+        }
+    }
+    if len(mismatches) > 0 {
+        log.Printf("[ERROR][terraform-provider-hyperv] cannot import %s %q into terraform state\n", x.TypeName, id)
+        return diag.Errorf("[terraform-provider-hyperv/hyperv/tfutil/importExisting()] cannot import %s %q into terraform state when terraform config doesn't match the properties in infrastructure: %s", x.TypeName, id, strings.Join(mismatches, ", "))
+    }
+
+    // update existing resource
+    if x.Adopt != nil {
+        err = x.Adopt(d, m, existing)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-hyperv] cannot update existing %s %q\n", x.TypeName, id)
+            log.Printf("[ERROR][terraform-provider-hyperv] cannot import %s %q into terraform state\n", x.TypeName, id)
+            return diag.FromErr(err)
+        }
+    }
+
+    // set computed lifecycle properties
+    x_lifecycle["imported"] = true
+    SetResourceDataMap(d, "x_lifecycle", x_lifecycle)
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-hyperv] imported %s %q into terraform state\n", x.TypeName, id)
+    return read(ctx, d, m)
+}
+
+func (x *ResourceXLifecycle) read(read schema.ReadContextFunc) schema.ReadContextFunc {
+    return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+        x_lifecycle := GetResourceDataMap(d, "x_lifecycle")
+
+        diags := read(ctx, d, m)
+        if diags.HasError() {
+            return diags
+        }
+
+        SetResourceDataMap(d, "x_lifecycle", x_lifecycle)   // make sure new terraform state includes 'x_lifecycle' from the old terraform state when doing a terraform refresh
+        return diags
+    }
+}
+
+func (x *ResourceXLifecycle) delete(delete schema.DeleteContextFunc) schema.DeleteContextFunc {
+    return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+        x_lifecycle := GetResourceDataMap(d, "x_lifecycle")
+
+        // lifecycle customizations: destroy_if_imported
+        if x_lifecycle != nil {
+            imported := x_lifecycle["imported"].(bool)
+            destroy_if_imported := x_lifecycle["destroy_if_imported"].(bool)
+            if imported && !destroy_if_imported {
+                id := d.Id()
+
+                log.Printf("[INFO][terraform-provider-hyperv] %s %q was imported and must not be deleted from infrastructure\n", x.TypeName, id)
+
+                // set id
+                d.SetId("")
+
+                log.Printf("[INFO][terraform-provider-hyperv] deleted %s %q from terraform state, no change in infrastructure\n", x.TypeName, id)
+                return nil
+            }
+        }
+
+        // no lifecycle customizations
+        return delete(ctx, d, m)
+    }
+}
+
+func (x *ResourceXLifecycle) alreadyExists(diags diag.Diagnostics) bool {
+    for _, d := range diags {
+        if d.Severity != diag.Error {
+            continue
+        }
+        if x.AlreadyExists != nil {
+            if x.AlreadyExists(diagError(d)) {
+                return true
+            }
+        } else if strings.Contains(d.Summary, "already exists") || strings.Contains(d.Detail, "already exists") {
+            return true
+        }
+    }
+    return false
+}
+
+func matchAttribute(config interface{}, existing interface{}) bool {
+    if c, ok := config.(string); ok {
+        if e, ok := existing.(string); ok {
+            return strings.EqualFold(c, e)
+        }
+    }
+    return reflect.DeepEqual(config, existing)
+}
+
+//------------------------------------------------------------------------------
+
+// DataSourceXLifecycle describes a data-source for the generic implementation of the 'x_lifecycle' customizations for data-sources
+//     "ignore_error_if_not_exists" and "exists"
+type DataSourceXLifecycle struct {
+    TypeName  string   // the terraform type of the data-source, f.i. "hyperv_vswitch"
+
+    // ID returns the id of the data-source from the config
+    ID        func(d *schema.ResourceData, m interface{}) string
+
+    // NotExists returns true when the error from reading the data-source means the data-source doesn't exist (optional)
+    // defaults to checking the error for "cannot find" or "doesn't exist"
+    NotExists func(err error) bool
+}
+
+// WithDataSourceXLifecycle adds the 'x_lifecycle' customizations to a data-source
+// the 'x_lifecycle' schema is added when the data-source doesn't define its own (extended) schema
+func WithDataSourceXLifecycle(r *schema.Resource, x *DataSourceXLifecycle) *schema.Resource {
+    if _, ok := r.Schema["x_lifecycle"]; !ok {
+        r.Schema["x_lifecycle"] = &DataSourceXLifecycleSchema
+    }
+
+    r.ReadContext = x.read(r.ReadContext, r.Schema)
+    return r
+}
+
+func (x *DataSourceXLifecycle) read(read schema.ReadContextFunc, s map[string]*schema.Schema) schema.ReadContextFunc {
+    return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+        id          := x.ID(d, m)
+        x_lifecycle := GetResourceDataMap(d, "x_lifecycle")
+
+        diags := read(ctx, d, m)
+        if diags.HasError() {
+            // lifecycle customizations: ignore_error_if_not_exists
+            if x_lifecycle != nil {
+                ignore_error_if_not_exists := x_lifecycle["ignore_error_if_not_exists"].(bool)
+                if ignore_error_if_not_exists && x.notExists(diags) {
+                    // set zeroed properties
+                    for k := range s {
+                        if k != "x_lifecycle" {
+                            d.Set(k, nil)
+                        }
+                    }
+
+                    // set computed lifecycle properties
+                    x_lifecycle["exists"] = false
+                    SetResourceDataMap(d, "x_lifecycle", x_lifecycle)
+
+                    // set id
+                    d.SetId(id)
+
+                    log.Printf("[INFO][terraform-provider-hyperv] ignored error and added zeroed %s %q to terraform state\n", x.TypeName, id)
+                    return nil
+                }
+            }
+
+            // no lifecycle customizations
+            return diags
+        }
+
+        // set computed lifecycle properties
+        if x_lifecycle != nil {
+            x_lifecycle["exists"] = true
+            SetResourceDataMap(d, "x_lifecycle", x_lifecycle)
+        }
+        return diags
+    }
+}
+
+func (x *DataSourceXLifecycle) notExists(diags diag.Diagnostics) bool {
+    for _, d := range diags {
+        if d.Severity != diag.Error {
+            continue
+        }
+        if x.NotExists != nil {
+            if x.NotExists(diagError(d)) {
+                return true
+            }
+        } else if strings.Contains(d.Summary, "cannot find") || strings.Contains(d.Summary, "doesn't exist") {
+            return true
+        }
+    }
+    return false
+}
+
+// diagError returns the error for an error diagnostic, to check it with the 'AlreadyExists' and 'NotExists' functions
+func diagError(d diag.Diagnostic) error {
+    if d.Detail != "" {
+        return errors.New(d.Summary + ": " + d.Detail)
+    }
+    return errors.New(d.Summary)
+}
+
+//------------------------------------------------------------------------------
@@ -7,13 +7,74 @@
 package main
 
 import (
-    "github.com/hashicorp/terraform-plugin-sdk/plugin"
+    "flag"
+    "fmt"
+    "io/ioutil"
+    "log"
+    "os"
+    "strings"
 
+    "github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+
+    "github.com/stefaanc/terraform-provider-hyperv/api"
     "github.com/stefaanc/terraform-provider-hyperv/hyperv"
 )
 
 func main() {
+    // subcommands, when running the binary outside terraform
+    if len(os.Args) > 1 {
+        switch os.Args[1] {
+        case "gc":
+            os.Exit(gc(os.Args[2:]))
+        case "export":
+            os.Exit(export(os.Args[2:]))
+        }
+    }
+
     plugin.Serve(&plugin.ServeOpts{
         ProviderFunc: hyperv.Provider,
     })
-}
\ No newline at end of file
+}
+
+//------------------------------------------------------------------------------
+
+// connectionFlags adds the provider's connection settings to the flags of a subcommand
+// the password defaults to the HYPERV_PASSWORD environment variable, to keep it out of the command line
+func connectionFlags(flags *flag.FlagSet) func() (*api.HypervClient, error) {
+    config := new(hyperv.Config)
+
+    connectionType := flags.String("type", "local", "the type of connection to the hyperv-server: \"local\" or \"ssh\"")
+    flags.StringVar(&config.Host, "host", "localhost", "the hyperv-server, when -type is \"ssh\"")
+    port := flags.Uint("port", 22, "the hyperv-server's port for ssh, when -type is \"ssh\"")
+    flags.StringVar(&config.User, "user", "", "the user name for communication with the hyperv-server, when -type is \"ssh\"")
+    flags.StringVar(&config.Password, "password", "", "the user password for communication with the hyperv-server, when -type is \"ssh\" (default $HYPERV_PASSWORD)")
+    flags.BoolVar(&config.Insecure, "insecure", false, "allow insecure communication - disables checking of the server certificate")
+    flags.StringVar(&config.NamePrefix, "name-prefix", "", "the provider's \"name_prefix\"")
+    verbose := flags.Bool("verbose", false, "write the provider's log to stderr")
+
+    return func() (*api.HypervClient, error) {
+        if !*verbose {
+            log.SetOutput(ioutil.Discard)
+        }
+
+        config.Type = strings.ToLower(*connectionType)
+        if config.Type != "local" && config.Type != "ssh" {
+            return nil, fmt.Errorf("-type must be \"local\" or \"ssh\"")
+        }
+        if *port > 65535 {
+            return nil, fmt.Errorf("-port must be between 0 and 65535")
+        }
+        config.Port = uint16(*port)
+        if config.Password == "" {
+            config.Password = os.Getenv("HYPERV_PASSWORD")
+        }
+
+        c, err := config.Client()
+        if err != nil {
+            return nil, err
+        }
+        return c.(*api.HypervClient), nil
+    }
+}
+
+//------------------------------------------------------------------------------